@@ -0,0 +1,163 @@
+/*
+Package ansi provides a streaming parser for byte streams with ANSI escape
+sequences (ECMA-48) that converts them into text, control characters and
+escape sequences, and functions for building escape sequences.
+
+It can be used for filtering, recording or rewriting the output of terminal
+programs and for decoding the input from a terminal.
+*/
+package ansi
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Type is the type of a sequence.
+type Type uint8
+
+const (
+	Text    Type = iota // printable text
+	Control             // a C0 control character (e.g. "\n" or BEL)
+	ESC                 // ESC <intermediate bytes> <final byte>
+	CSI                 // control sequence: ESC [ <parameters> <final byte>
+	OSC                 // operating system command: ESC ] <data> BEL or ST
+	DCS                 // device control string: ESC P <parameters> <final byte> <data> ST
+	String              // APC (ESC _), PM (ESC ^) or SOS (ESC X) string: <data> ST
+)
+
+var typeNames = []string{"Text", "Control", "ESC", "CSI", "OSC", "DCS", "String"}
+
+func (t Type) String() string {
+	if int(t) < len(typeNames) {
+		return typeNames[t]
+	}
+	return "Type(" + strconv.Itoa(int(t)) + ")"
+}
+
+// Sequence is a part of a byte stream.
+type Sequence struct {
+	Type Type
+	// private marker of CSI and DCS sequences ('<', '=', '>' or '?') or 0;
+	// introducer ('_', '^' or 'X') of String sequences
+	Prefix byte
+	// parameters of CSI and DCS sequences with their sub-parameters
+	// (separated by ':'); -1 is an omitted value
+	Params       [][]int
+	Intermediate []byte
+	Final        byte
+	// Text and Control: the bytes; OSC, DCS and String: the data
+	Data []byte
+	// OSC: terminated with BEL instead of ST
+	BEL bool
+}
+
+// Param returns the first sub-parameter of parameter i or def if it
+// is omitted.
+func (s *Sequence) Param(i, def int) int {
+	return s.SubParam(i, 0, def)
+}
+
+// SubParam returns the sub-parameter j of parameter i or def if it
+// is omitted.
+func (s *Sequence) SubParam(i, j, def int) int {
+	if i < len(s.Params) && j < len(s.Params[i]) && s.Params[i][j] >= 0 {
+		return s.Params[i][j]
+	}
+	return def
+}
+
+// String returns the sequence as it appears in a byte stream.
+func (s *Sequence) String() string {
+	var b strings.Builder
+	switch s.Type {
+	case Text, Control:
+		b.Write(s.Data)
+	case ESC:
+		b.WriteByte(esc)
+		b.Write(s.Intermediate)
+		b.WriteByte(s.Final)
+	case CSI, DCS:
+		if s.Type == CSI {
+			b.WriteString("\x1b[")
+		} else {
+			b.WriteString("\x1bP")
+		}
+		if s.Prefix != 0 {
+			b.WriteByte(s.Prefix)
+		}
+		b.WriteString(formatParams(s.Params))
+		b.Write(s.Intermediate)
+		b.WriteByte(s.Final)
+		if s.Type == DCS {
+			b.Write(s.Data)
+			b.WriteString(st)
+		}
+	case OSC:
+		b.WriteString("\x1b]")
+		b.Write(s.Data)
+		if s.BEL {
+			b.WriteByte(bel)
+		} else {
+			b.WriteString(st)
+		}
+	case String:
+		b.WriteByte(esc)
+		b.WriteByte(s.Prefix)
+		b.Write(s.Data)
+		b.WriteString(st)
+	}
+	return b.String()
+}
+
+func formatParams(params [][]int) string {
+	var b strings.Builder
+	for i, p := range params {
+		if i > 0 {
+			b.WriteByte(';')
+		}
+		for j, n := range p {
+			if j > 0 {
+				b.WriteByte(':')
+			}
+			if n >= 0 {
+				b.WriteString(strconv.Itoa(n))
+			}
+		}
+	}
+	return b.String()
+}
+
+const (
+	bel = 0x07
+	can = 0x18
+	sub = 0x1A
+	esc = 0x1B
+	del = 0x7F
+	st  = "\x1b\\"
+)
+
+// BuildCSI returns a control sequence with the parameters and the final byte.
+//   ansi.BuildCSI('m', 1, 31) -> "\x1b[1;31m"
+func BuildCSI(final byte, params ...int) string {
+	return BuildPrivateCSI(0, final, params...)
+}
+
+// BuildPrivateCSI returns a control sequence with the private marker prefix
+// (0 for none), the parameters and the final byte.
+//   ansi.BuildPrivateCSI('?', 'h', 2004) -> "\x1b[?2004h"
+func BuildPrivateCSI(prefix, final byte, params ...int) string {
+	s := Sequence{Type: CSI, Prefix: prefix, Final: final}
+	for _, p := range params {
+		s.Params = append(s.Params, []int{p})
+	}
+	return s.String()
+}
+
+// BuildOSC returns an operating system command with the arguments separated
+// by ';' and terminated with BEL.
+//   ansi.BuildOSC("0", "title") -> "\x1b]0;title\a"
+func BuildOSC(args ...string) string {
+	s := Sequence{Type: OSC, Data: []byte(strings.Join(args, ";")), BEL: true}
+	return s.String()
+}
@@ -0,0 +1,207 @@
+package ansi
+
+import "unicode/utf8"
+
+type state uint8
+
+const (
+	stateGround    state = iota
+	stateEscape          // after ESC
+	stateCSI             // parameters, intermediate and final byte
+	stateDCS             // parameters, intermediate and final byte
+	stateString          // data of OSC, DCS and String sequences
+	stateStringEsc       // ESC in a string (maybe the start of ST)
+)
+
+// Parser is a streaming parser. Bytes that do not form a complete
+// sequence are kept until more bytes are fed. The zero value is ready
+// to use.
+//
+// The parser follows the state machine of DEC compatible terminals with
+// these simplifications: 8-bit C1 control codes are not recognized (the
+// bytes are part of UTF-8 encoded text) and control characters within
+// escape sequences are returned before the sequence.
+type Parser struct {
+	state   state
+	pending []byte // bytes of the current sequence
+	seq     Sequence
+	text    []byte // text that is not yet returned
+}
+
+// Feed parses the bytes b and returns the sequences that are complete.
+// Adjacent text is returned as one Text sequence; an incomplete UTF-8
+// encoded rune at the end is kept.
+//   var p ansi.Parser
+//   for _, seq := range p.Feed(buf[:n]) {
+//       if seq.Type == ansi.Text {
+//           ...
+//       }
+//   }
+func (p *Parser) Feed(b []byte) []Sequence {
+	var seqs []Sequence
+	emit := func(s Sequence) {
+		if len(p.text) > 0 {
+			seqs = append(seqs, Sequence{Type: Text, Data: p.text})
+			p.text = nil
+		}
+		seqs = append(seqs, s)
+	}
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+		if p.state != stateString && p.state != stateStringEsc {
+			switch {
+			case c == can || c == sub:
+				// cancel the sequence
+				p.reset()
+				emit(Sequence{Type: Control, Data: []byte{c}})
+				continue
+			case c == esc:
+				if p.state != stateGround || len(p.pending) > 0 {
+					// an incomplete sequence is dropped
+					p.reset()
+				}
+				p.state = stateEscape
+				p.pending = append(p.pending, c)
+				continue
+			case c < 0x20:
+				emit(Sequence{Type: Control, Data: []byte{c}})
+				continue
+			case c == del:
+				// ignored in escape sequences
+				if p.state == stateGround {
+					emit(Sequence{Type: Control, Data: []byte{c}})
+				}
+				continue
+			}
+		}
+		switch p.state {
+		case stateGround:
+			p.text = append(p.text, c)
+		case stateEscape:
+			p.pending = append(p.pending, c)
+			switch {
+			case c >= 0x20 && c <= 0x2F:
+				p.seq.Intermediate = append(p.seq.Intermediate, c)
+			case len(p.seq.Intermediate) > 0:
+				p.seq.Type, p.seq.Final = ESC, c
+				emit(p.done())
+			case c == '[':
+				p.seq.Type, p.state = CSI, stateCSI
+			case c == 'P':
+				p.seq.Type, p.state = DCS, stateDCS
+			case c == ']':
+				p.seq.Type, p.state = OSC, stateString
+			case c == '_' || c == '^' || c == 'X':
+				p.seq.Type, p.seq.Prefix, p.state = String, c, stateString
+			default:
+				p.seq.Type, p.seq.Final = ESC, c
+				emit(p.done())
+			}
+		case stateCSI, stateDCS:
+			p.pending = append(p.pending, c)
+			switch {
+			case c >= 0x3C && c <= 0x3F && len(p.pending) == 3:
+				p.seq.Prefix = c
+			case c >= 0x30 && c <= 0x3F && len(p.seq.Intermediate) == 0:
+				p.param(c)
+			case c >= 0x20 && c <= 0x2F:
+				p.seq.Intermediate = append(p.seq.Intermediate, c)
+			case c >= 0x40 && c <= 0x7E:
+				p.seq.Final = c
+				if p.state == stateDCS {
+					p.state = stateString
+				} else {
+					emit(p.done())
+				}
+			default:
+				// invalid sequence
+				p.reset()
+			}
+		case stateString:
+			p.pending = append(p.pending, c)
+			switch {
+			case c == esc:
+				p.state = stateStringEsc
+			case c == bel && p.seq.Type == OSC:
+				p.seq.BEL = true
+				emit(p.done())
+			default:
+				p.seq.Data = append(p.seq.Data, c)
+			}
+		case stateStringEsc:
+			p.pending = append(p.pending, c)
+			if c == '\\' {
+				emit(p.done())
+			} else {
+				// the string is terminated by the ESC of another sequence,
+				// c is processed again in the new state
+				emit(p.done())
+				p.state = stateEscape
+				p.pending = append(p.pending, esc)
+				i--
+			}
+		}
+	}
+	if len(p.text) > 0 {
+		// keep an incomplete rune
+		n := len(p.text)
+		i := n - 1
+		for i > 0 && n-i < utf8.UTFMax && !utf8.RuneStart(p.text[i]) {
+			i--
+		}
+		if utf8.FullRune(p.text[i:]) {
+			i = n
+		}
+		if i > 0 {
+			seqs = append(seqs, Sequence{Type: Text, Data: p.text[:i]})
+			p.text = append([]byte(nil), p.text[i:]...)
+		}
+	}
+	return seqs
+}
+
+// param adds a byte to the parameters.
+func (p *Parser) param(c byte) {
+	s := &p.seq
+	if s.Params == nil {
+		s.Params = [][]int{{-1}}
+	}
+	last := s.Params[len(s.Params)-1]
+	switch {
+	case c >= '0' && c <= '9':
+		n := last[len(last)-1]
+		if n < 0 {
+			n = 0
+		}
+		if n < 1e8 {
+			last[len(last)-1] = n*10 + int(c-'0')
+		}
+	case c == ';':
+		s.Params = append(s.Params, []int{-1})
+	case c == ':':
+		s.Params[len(s.Params)-1] = append(last, -1)
+	}
+}
+
+// done returns the current sequence and resets the parser.
+func (p *Parser) done() Sequence {
+	s := p.seq
+	p.reset()
+	return s
+}
+
+func (p *Parser) reset() {
+	p.state = stateGround
+	p.pending = nil
+	p.seq = Sequence{}
+}
+
+// Pending returns the bytes of an incomplete sequence and resets the state
+// of the parser, e.g. to return a single ESC as a key if no more bytes
+// follow within a timeout.
+func (p *Parser) Pending() []byte {
+	b := append(p.text, p.pending...)
+	p.text = nil
+	p.reset()
+	return b
+}
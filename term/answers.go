@@ -0,0 +1,215 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrNoAnswer is returned in non-interactive mode if there is neither an answer
+// nor a default value for a prompt.
+var ErrNoAnswer = errors.New("no answer")
+
+// AnswerProvider provides the answers for prompts in non-interactive mode.
+// The answers are converted as if they were typed in by the user.
+type AnswerProvider interface {
+	// Answer returns the answer for the prompt with the key and
+	// whether there was one.
+	Answer(key string) (string, bool)
+}
+
+var (
+	answers        AnswerProvider
+	nonInteractive bool
+)
+
+// SetAnswers sets the answer provider for the non-interactive mode
+// (nil removes it). The non-interactive mode is used if an answer provider
+// is set and stdin is not connected to a terminal or the mode was enabled
+// with SetNonInteractive.
+//
+// In non-interactive mode the functions Input, YesNo, Select, Menu,
+// MenuWithDefault, MenuWithOpt and Form.Run do not require a terminal.
+// The key for the answer is InputOpt.Key, the name of a form field, or
+// the prompt without surrounding spaces and a trailing ':' or '?'. If there
+// is no answer, the default value is used; if there is none, an error
+// wrapping ErrNoAnswer is returned.
+func SetAnswers(p AnswerProvider) {
+	answers = p
+}
+
+// SetNonInteractive enables or disables the non-interactive mode
+// regardless of stdin being connected to a terminal.
+func SetNonInteractive(b bool) {
+	nonInteractive = b
+}
+
+func isNonInteractive() bool {
+	return answers != nil && (nonInteractive || !IsTerminal(inFile.Fd()))
+}
+
+// checkCanInput is checkIsTerminal for functions that support
+// the non-interactive mode.
+func checkCanInput() {
+	if !isNonInteractive() {
+		checkIsTerminal()
+	}
+}
+
+func promptKey(prompt string) string {
+	return strings.TrimRight(strings.TrimSpace(prompt), ":? ")
+}
+
+func inputKey(prompt string, opt *InputOpt) string {
+	if opt.Key != "" {
+		return opt.Key
+	}
+	return promptKey(prompt)
+}
+
+func inputAnswer(prompt string, in interface{}, opt *InputOpt, conv func(string) error) error {
+	key := inputKey(prompt, opt)
+	s, ok := answers.Answer(key)
+	if !ok || s == "" {
+		if opt.Default == nil {
+			return fmt.Errorf("%w: %s", ErrNoAnswer, key)
+		}
+		setValue(in, opt.Default)
+		writeOut(func() { fmt.Fprintln(out, prompt) })
+		answered(prompt, opt, "")
+		return nil
+	}
+	if err := conv(s); err != nil {
+		return fmt.Errorf("invalid answer for %s: %w", key, &ConversionError{s, err})
+	}
+	writeOut(func() {
+		if opt.Echo == EchoNormal {
+			fmt.Fprintln(out, prompt+s)
+		} else {
+			fmt.Fprintln(out, prompt)
+		}
+	})
+	answered(prompt, opt, s)
+	return nil
+}
+
+// MapAnswers is an AnswerProvider with answers from a map.
+type MapAnswers map[string]string
+
+// Answer implements the AnswerProvider interface.
+func (m MapAnswers) Answer(key string) (string, bool) {
+	s, ok := m[key]
+	return s, ok
+}
+
+type envAnswers string
+
+func (prefix envAnswers) Answer(key string) (string, bool) {
+	name := strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, key)
+	return os.LookupEnv(string(prefix) + name)
+}
+
+// EnvAnswers returns an AnswerProvider that gets the answers from
+// environment variables. The name of a variable is the key of
+// the answer in upper case with the prefix prepended and all characters
+// that are not ASCII letters or digits replaced by '_'.
+//   term.EnvAnswers("APP_") -> key "Proxy address" -> $APP_PROXY_ADDRESS
+func EnvAnswers(prefix string) AnswerProvider {
+	return envAnswers(prefix)
+}
+
+// FileAnswers returns an AnswerProvider with answers read from a file.
+// If the file name ends with ".yaml" or ".yml", the file must contain lines
+// with "key: value" pairs (only this flat subset of YAML is supported),
+// otherwise it must contain a JSON object whose values are strings, numbers
+// or booleans.
+func FileAnswers(name string) (AnswerProvider, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	m := MapAnswers{}
+	switch filepath.Ext(name) {
+	case ".yaml", ".yml":
+		scanner := bufio.NewScanner(f)
+		for n := 1; scanner.Scan(); n++ {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || line[0] == '#' || line == "---" {
+				continue
+			}
+			i := strings.Index(line, ":")
+			if i < 0 {
+				return nil, fmt.Errorf("%s:%d: missing ':'", name, n)
+			}
+			m[yamlScalar(line[:i])] = yamlScalar(line[i+1:])
+		}
+		err = scanner.Err()
+	default:
+		var obj map[string]interface{}
+		if err = json.NewDecoder(f).Decode(&obj); err == nil {
+			for k, v := range obj {
+				switch v.(type) {
+				case string, float64, bool:
+					m[k] = fmt.Sprint(v)
+				default:
+					return nil, fmt.Errorf("%s: invalid value for %q", name, k)
+				}
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func yamlScalar(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 {
+		switch s[0] {
+		case '"':
+			if u, err := strconv.Unquote(s); err == nil {
+				return u
+			}
+		case '\'':
+			if s[len(s)-1] == '\'' {
+				return strings.ReplaceAll(s[1:len(s)-1], "''", "'")
+			}
+		}
+	}
+	if i := strings.Index(s, " #"); i >= 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+	return s
+}
+
+type chainAnswers []AnswerProvider
+
+func (c chainAnswers) Answer(key string) (string, bool) {
+	for _, p := range c {
+		if s, ok := p.Answer(key); ok {
+			return s, ok
+		}
+	}
+	return "", false
+}
+
+// ChainAnswers returns an AnswerProvider that asks the providers in
+// the given order and returns the first answer found.
+func ChainAnswers(providers ...AnswerProvider) AnswerProvider {
+	return chainAnswers(providers)
+}
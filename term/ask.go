@@ -0,0 +1,187 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Ask gets input for the fields of the struct to which v points. Only exported
+// fields with a "prompt" tag are used. The tag "default" sets the default value
+// and the tag "validate" contains comma separated rules the input must satisfy:
+//   range=MIN-MAX  numeric value must be in the range
+//   len=MIN-MAX    number of characters must be in the range
+// MIN or MAX may be left out. A field of type bool is asked with YesNo.
+//   type Config struct {
+//       Host string `prompt:"Host" default:"localhost"`
+//       Port int    `prompt:"Port" default:"8080" validate:"range=1-65535"`
+//   }
+//   var cfg Config
+//   err := term.Ask(&cfg)
+// It returns an error if v is not a pointer to a struct or if a tag is invalid
+// (also if a default value does not satisfy the rules). The struct is only
+// changed if the input for all fields was successful.
+// It panics if stdin and stdout are not connected to a terminal (unless in
+// non-interactive mode, see SetAnswers).
+func Ask(v interface{}) error {
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Ptr || val.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("type of 'v' not a pointer to a struct: %s", val.Type())
+	}
+	val = val.Elem()
+	typ := val.Type()
+	form := &Form{}
+	var indexes []int
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		prompt, ok := sf.Tag.Lookup("prompt")
+		if !ok || sf.PkgPath != "" {
+			continue
+		}
+		// the input is assigned to a copy of the field
+		ptr := reflect.New(sf.Type)
+		ptr.Elem().Set(val.Field(i))
+		fld, err := askField(sf, ptr.Interface(), prompt)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", sf.Name, err)
+		}
+		form.Fields = append(form.Fields, fld)
+		indexes = append(indexes, i)
+	}
+	if err := form.Run(); err != nil {
+		return err
+	}
+	for j, fld := range form.Fields {
+		val.Field(indexes[j]).Set(reflect.ValueOf(fld.Value).Elem())
+	}
+	return nil
+}
+
+func askField(sf reflect.StructField, ptr interface{}, prompt string) (*Field, error) {
+	opt := &InputOpt{}
+	fld := &Field{Name: sf.Name, Value: ptr, Opt: opt}
+	var checks []func(reflect.Value) bool
+	if s, ok := sf.Tag.Lookup("validate"); ok {
+		for _, rule := range strings.Split(s, ",") {
+			check, err := parseRule(strings.TrimSpace(rule), sf.Type)
+			if err != nil {
+				return nil, err
+			}
+			checks = append(checks, check)
+		}
+	}
+	if s, ok := sf.Tag.Lookup("default"); ok {
+		v, err := scanValue(s, sf.Type)
+		if err != nil {
+			return nil, fmt.Errorf("invalid default value: %q", s)
+		}
+		for _, check := range checks {
+			if !check(reflect.ValueOf(v)) {
+				return nil, fmt.Errorf("default value does not satisfy the rules: %q", s)
+			}
+		}
+		opt.Default = v
+		if sf.Type.Kind() != reflect.Bool {
+			prompt = fmt.Sprintf("%s [%s]", prompt, s)
+		}
+	}
+	if sf.Type.Kind() != reflect.Bool {
+		prompt += ": "
+	}
+	fld.Prompt = prompt
+	opt.ConvFunc = func(s string) (interface{}, error) {
+		v, err := scanValue(s, sf.Type)
+		if err != nil {
+			return nil, err
+		}
+		rv := reflect.ValueOf(v)
+		for _, check := range checks {
+			if !check(rv) {
+				return nil, errors.New("validation failed")
+			}
+		}
+		return v, nil
+	}
+	return fld, nil
+}
+
+// scanValue converts s to a value of type typ.
+func scanValue(s string, typ reflect.Type) (interface{}, error) {
+	ptr := reflect.New(typ)
+	if typ.Kind() == reflect.String {
+		ptr.Elem().SetString(s)
+	} else if _, err := fmt.Sscan(s, ptr.Interface()); err != nil {
+		return nil, err
+	}
+	return ptr.Elem().Interface(), nil
+}
+
+func parseRule(rule string, typ reflect.Type) (func(reflect.Value) bool, error) {
+	name, arg := rule, ""
+	if i := strings.IndexByte(rule, '='); i >= 0 {
+		name, arg = rule[:i], rule[i+1:]
+	}
+	// the separator is searched after the first character so that MIN can be negative
+	i := strings.IndexByte(arg, '-')
+	if len(arg) > 1 {
+		if j := strings.IndexByte(arg[1:], '-'); j >= 0 {
+			i = j + 1
+		}
+	}
+	if i < 0 {
+		return nil, fmt.Errorf("invalid rule: %q", rule)
+	}
+	min, max, err := parseBounds(arg[:i], arg[i+1:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid rule: %q", rule)
+	}
+	switch name {
+	case "range":
+		var num func(reflect.Value) float64
+		switch typ.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			num = func(v reflect.Value) float64 { return float64(v.Int()) }
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			num = func(v reflect.Value) float64 { return float64(v.Uint()) }
+		case reflect.Float32, reflect.Float64:
+			num = func(v reflect.Value) float64 { return v.Float() }
+		default:
+			return nil, fmt.Errorf("rule %q requires a numeric type", name)
+		}
+		return func(v reflect.Value) bool {
+			x := num(v)
+			return x >= min && x <= max
+		}, nil
+	case "len":
+		if typ.Kind() != reflect.String {
+			return nil, fmt.Errorf("rule %q requires a string type", name)
+		}
+		return func(v reflect.Value) bool {
+			n := float64(utf8.RuneCountInString(v.String()))
+			return n >= min && n <= max
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown rule: %q", rule)
+}
+
+func parseBounds(minStr, maxStr string) (float64, float64, error) {
+	min, max := -math.MaxFloat64, math.MaxFloat64
+	var err error
+	if minStr != "" {
+		if min, err = strconv.ParseFloat(minStr, 64); err != nil {
+			return 0, 0, err
+		}
+	}
+	if maxStr != "" {
+		if max, err = strconv.ParseFloat(maxStr, 64); err != nil {
+			return 0, 0, err
+		}
+	}
+	return min, max, nil
+}
@@ -0,0 +1,77 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import "strings"
+
+// BannerOpt contains the options for the BannerWithOpt function.
+type BannerOpt struct {
+	Width    int   // max. width; default: width of the terminal
+	From, To Color // colors of a gradient from left to right (if both are set)
+}
+
+// Banner returns text in large letters of the font (e.g. SmallFont) for
+// headers of a program; see BannerWithOpt.
+//   fmt.Print(term.Banner("Hello", term.SmallFont))
+func Banner(text string, font Font) string {
+	return BannerWithOpt(text, font, nil)
+}
+
+// BannerWithOpt returns text in large letters of the font. The text
+// is wrapped at spaces (or within words that are too long) to fit into
+// the width; newlines in text start new lines, too. The lines are separated
+// by an empty line. Characters without a glyph in the font are skipped.
+// opt may be nil.
+func BannerWithOpt(text string, font Font, opt *BannerOpt) string {
+	if opt == nil {
+		opt = &BannerOpt{}
+	}
+	width := chartWidth(opt.Width)
+	var blocks []string
+	for _, line := range bannerLines(text, font, width) {
+		rows := font.render(line)
+		if opt.From != 0 && opt.To != 0 {
+			for i, row := range rows {
+				rows[i] = Gradient(opt.From, opt.To).renderColumns(row, font.width(line))
+			}
+		}
+		blocks = append(blocks, strings.Join(rows, "\n")+"\n")
+	}
+	return strings.Join(blocks, "\n")
+}
+
+// bannerLines splits text into lines that fit into width when they are
+// rendered in the font.
+func bannerLines(text string, font Font, width int) []string {
+	var lines []string
+	for _, para := range strings.Split(text, "\n") {
+		var line string
+		for _, word := range strings.Fields(para) {
+			switch {
+			case line == "":
+				line = word
+			case font.width(line+" "+word) <= width:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+			// a word that is too long is split
+			for font.width(line) > width {
+				var head []rune
+				for _, ch := range line {
+					if len(head) > 0 && font.width(string(append(head, ch))) > width {
+						break
+					}
+					head = append(head, ch)
+				}
+				lines = append(lines, string(head))
+				line = line[len(string(head)):]
+			}
+		}
+		if line != "" || len(lines) == 0 {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
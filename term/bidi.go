@@ -0,0 +1,147 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// BidiFunc converts a line of text from logical to visual order. It returns
+// the runes in visual order and, for each rune in logical order, its index
+// in the visual order.
+type BidiFunc func(logical []rune) (visual []rune, index []int)
+
+var bidiFunc BidiFunc
+
+// SetBidi sets the function that is used by the input functions to reorder
+// the prompt and the input if it is echoed normally (nil disables reordering,
+// which is the default). It is only needed for terminals that do not
+// implement the bidirectional algorithm themselves. The function BasicBidi
+// can be used or a function using a full implementation of the Unicode
+// bidirectional algorithm.
+func SetBidi(f BidiFunc) {
+	bidiFunc = f
+}
+
+var runeWidthFunc func(rune) int
+
+// SetRuneWidthFunc sets a function that is used instead of the built-in
+// rules by RuneWidth and thus for all cursor movements of the input
+// functions (nil restores the built-in rules).
+func SetRuneWidthFunc(f func(r rune) int) {
+	runeWidthFunc = f
+}
+
+// isRTL returns true if r is a letter of a right-to-left script.
+func isRTL(r rune) bool {
+	return unicode.In(r, unicode.Hebrew, unicode.Arabic, unicode.Syriac,
+		unicode.Thaana, unicode.Nko)
+}
+
+// BasicBidi is a BidiFunc that implements a small subset of the Unicode
+// bidirectional algorithm: the paragraph direction is left-to-right, runs
+// of right-to-left letters (Hebrew, Arabic etc.) with the spaces, punctuation
+// and numbers between them are reversed, numbers within such runs are kept
+// in left-to-right order. Brackets are not mirrored.
+func BasicBidi(logical []rune) ([]rune, []int) {
+	order := make([]int, 0, len(logical)) // logical indices in visual order
+	for i := 0; i < len(logical); {
+		if !isRTL(logical[i]) {
+			order = append(order, i)
+			i++
+			continue
+		}
+		// find the end of the right-to-left run
+		end := i
+		for j := i + 1; j < len(logical); j++ {
+			r := logical[j]
+			if unicode.IsLetter(r) && !isRTL(r) {
+				break
+			}
+			if isRTL(r) || unicode.IsDigit(r) || unicode.In(r, unicode.Mn, unicode.Me) {
+				end = j
+			}
+		}
+		// reverse the run keeping numbers in order
+		for j := end; j >= i; {
+			k := j
+			for k > i && isNumberPart(logical, k) && isNumberPart(logical, k-1) {
+				k--
+			}
+			if k < j {
+				for m := k; m <= j; m++ {
+					order = append(order, m)
+				}
+			} else if unicode.In(logical[j], unicode.Mn, unicode.Me) && j > i {
+				// combining marks stay after their base character
+				k = j
+				for k > i && unicode.In(logical[k], unicode.Mn, unicode.Me) {
+					k--
+				}
+				for m := k; m <= j; m++ {
+					order = append(order, m)
+				}
+			} else {
+				order = append(order, j)
+			}
+			j = k - 1
+		}
+		i = end + 1
+	}
+	visual := make([]rune, len(logical))
+	index := make([]int, len(logical))
+	for v, l := range order {
+		visual[v] = logical[l]
+		index[l] = v
+	}
+	return visual, index
+}
+
+// isNumberPart returns true if the rune at index i is a digit or a '.' or ','
+// between digits.
+func isNumberPart(s []rune, i int) bool {
+	if unicode.IsDigit(s[i]) {
+		return true
+	}
+	return (s[i] == '.' || s[i] == ',') && i > 0 && i+1 < len(s) &&
+		unicode.IsDigit(s[i-1]) && unicode.IsDigit(s[i+1])
+}
+
+// bidi returns the function for reordering the input or nil.
+func (r *reader) bidi() BidiFunc {
+	if r.opt.Echo != EchoNormal {
+		return nil
+	}
+	return bidiFunc
+}
+
+// displayPrompt returns the prompt as it is shown on the screen.
+func (r *reader) displayPrompt() string {
+	prompt := r.modeIndicator() + r.prompt
+	if f := r.bidi(); f != nil {
+		visual, _ := f([]rune(prompt))
+		return string(visual)
+	}
+	return prompt
+}
+
+// bidiLayout returns the input in visual order and the column of the cursor.
+func (r *reader) bidiLayout(f BidiFunc) (string, int) {
+	visual, index := f([]rune(string(r.buf)))
+	p := utf8.RuneCount(r.buf[:r.pos])
+	if p == len(index) {
+		return string(visual), StringWidth(string(visual))
+	}
+	return string(visual), StringWidth(string(visual[:index[p]]))
+}
+
+// tailWidth returns the number of columns between the cursor and
+// the end of the input on the screen.
+func (r *reader) tailWidth() int {
+	if f := r.bidi(); f != nil {
+		s, col := r.bidiLayout(f)
+		return StringWidth(s) - col
+	}
+	return r.span(r.pos, len(r.buf))
+}
@@ -0,0 +1,413 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// ErrInvalidCast is returned by NewPlayer if the recording is not in
+// the asciicast v2 format.
+var ErrInvalidCast = errors.New("invalid asciicast recording")
+
+// castHeader is the first line of an asciicast v2 file.
+type castHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	IdleLimit float64           `json:"idle_time_limit,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// castEvent is an event of a recording: its time in seconds since the
+// start, its type ("o" for output, "i" for input, "r" for resize) and
+// its data.
+type castEvent struct {
+	Time float64
+	Type string
+	Data string
+}
+
+// Recorder records the output to the terminal (and optionally the input)
+// with its timing in the asciicast v2 format of asciinema
+// (https://asciinema.org) or in the ttyrec format, so that it can be
+// replayed with a Player or with other players for these formats.
+// It is safe for concurrent use.
+type Recorder struct {
+	mu    sync.Mutex
+	w     io.Writer
+	start time.Time
+	input bool
+	err   error
+	// ttyrec is true if the recording is in the ttyrec format.
+	ttyrec bool
+}
+
+// NewRecorder writes the header of a recording to w and returns
+// a Recorder that writes the events to w. The size of the terminal is
+// taken from stdout (80x24 if it is unknown). If input is true,
+// the input is recorded, too; note that this includes passwords.
+//   f, _ := os.Create("demo.cast")
+//   rec, err := term.NewRecorder(f, false)
+//   ...
+//   term.SetRecorder(rec)
+//   defer term.SetRecorder(nil)
+//   stdout := rec.Writer(os.Stdout)
+func NewRecorder(w io.Writer, input bool) (*Recorder, error) {
+	width, height, err := Size(false)
+	if err != nil || width == 0 || height == 0 {
+		width, height = 80, 24
+	}
+	r := &Recorder{w: w, start: time.Now(), input: input}
+	hdr := castHeader{
+		Version:   2,
+		Width:     int(width),
+		Height:    int(height),
+		Timestamp: r.start.Unix(),
+		Env:       map[string]string{"TERM": os.Getenv("TERM"), "SHELL": os.Getenv("SHELL")},
+	}
+	b, err := json.Marshal(hdr)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = w.Write(append(b, '\n')); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// event writes an event to the recording.
+func (r *Recorder) event(typ, data string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return
+	}
+	if r.ttyrec {
+		if typ == "o" {
+			r.err = writeTTYRecFrame(r.w, time.Now(), data)
+		}
+		return
+	}
+	t := math.Round(time.Since(r.start).Seconds()*1e6) / 1e6
+	b, err := json.Marshal([]interface{}{t, typ, data})
+	if err == nil {
+		_, err = r.w.Write(append(b, '\n'))
+	}
+	r.err = err
+}
+
+// Err returns the first error that occurred while writing the recording.
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// Resize records that the size of the terminal changed.
+func (r *Recorder) Resize(width, height int) {
+	r.event("r", strconv.Itoa(width)+"x"+strconv.Itoa(height))
+}
+
+// castStream records the bytes of a stream. A rune that is split across
+// two writes is recorded with the second one.
+type castStream struct {
+	r       *Recorder
+	typ     string
+	pending []byte
+}
+
+func (s *castStream) record(p []byte) {
+	b := append(s.pending, p...)
+	n := len(b)
+	for i := n - 1; i >= 0 && i > n-utf8.UTFMax; i-- {
+		if utf8.RuneStart(b[i]) {
+			if !utf8.FullRune(b[i:]) {
+				n = i
+			}
+			break
+		}
+	}
+	s.pending = append([]byte(nil), b[n:]...)
+	if n > 0 {
+		s.r.event(s.typ, string(b[:n]))
+	}
+}
+
+type castWriter struct {
+	w io.Writer
+	s castStream
+}
+
+func (cw *castWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.s.record(p[:n])
+	return n, err
+}
+
+// Writer returns a writer that writes to w and records the output.
+func (r *Recorder) Writer(w io.Writer) io.Writer {
+	return &castWriter{w: w, s: castStream{r: r, typ: "o"}}
+}
+
+type castReader struct {
+	r io.Reader
+	s castStream
+}
+
+func (cr *castReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.s.record(p[:n])
+	return n, err
+}
+
+// Reader returns a reader that reads from rd and records the input if
+// the Recorder records input.
+func (r *Recorder) Reader(rd io.Reader) io.Reader {
+	if !r.input {
+		return rd
+	}
+	return &castReader{r: rd, s: castStream{r: r, typ: "i"}}
+}
+
+var (
+	castRecorder *Recorder
+	recordInput  *castStream
+)
+
+// SetRecorder sets a Recorder that records the output of the input
+// functions and the keys they read (nil stops the recording). The output
+// of the program must be recorded with a writer returned by Recorder.Writer.
+func SetRecorder(r *Recorder) {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	outMu.Lock()
+	defer outMu.Unlock()
+	castRecorder = r
+	recordInput = nil
+	if r != nil && r.input {
+		recordInput = &castStream{r: r, typ: "i"}
+	}
+	setOutFile(outFile)
+}
+
+// outWriter returns the writer for out; outMu must be locked.
+func outWriter() io.Writer {
+	recentOutput.w = outFile
+	if castRecorder != nil {
+		recentOutput.w = castRecorder.Writer(outFile)
+	}
+	return &recentOutput
+}
+
+// Player replays a recording in the asciicast v2 or the ttyrec format.
+type Player struct {
+	Width  int // the width of the terminal of the recording (0 if unknown)
+	Height int // the height of the terminal of the recording (0 if unknown)
+	// the maximum time in seconds between two events (0: no limit)
+	IdleLimit float64
+	// the playback speed (e.g. 2 is twice as fast; 0 is the same as 1)
+	Speed  float64
+	events []castEvent
+	start  float64
+}
+
+// NewPlayer reads a recording in the asciicast v2 format from r.
+// It returns ErrInvalidCast if it is not in this format.
+func NewPlayer(r io.Reader) (*Player, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<24)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidCast
+	}
+	var hdr castHeader
+	if err := json.Unmarshal(scanner.Bytes(), &hdr); err != nil || hdr.Version != 2 {
+		return nil, ErrInvalidCast
+	}
+	p := &Player{Width: hdr.Width, Height: hdr.Height, IdleLimit: hdr.IdleLimit}
+	for line := 2; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var ev []interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil || len(ev) != 3 {
+			return nil, fmt.Errorf("%w: line %d", ErrInvalidCast, line)
+		}
+		t, ok1 := ev[0].(float64)
+		typ, ok2 := ev[1].(string)
+		data, ok3 := ev[2].(string)
+		if !ok1 || !ok2 || !ok3 {
+			return nil, fmt.Errorf("%w: line %d", ErrInvalidCast, line)
+		}
+		p.events = append(p.events, castEvent{t, typ, data})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// times returns the times of the events in seconds with the pauses
+// longer than IdleLimit shortened.
+func (p *Player) times() []float64 {
+	times := make([]float64, len(p.events))
+	var last, skipped float64
+	for i, ev := range p.events {
+		if p.IdleLimit > 0 && ev.Time-last > p.IdleLimit {
+			skipped += ev.Time - last - p.IdleLimit
+		}
+		last = ev.Time
+		times[i] = ev.Time - skipped
+	}
+	return times
+}
+
+// Duration returns the duration of the recording (with the pauses
+// shortened to IdleLimit).
+func (p *Player) Duration() time.Duration {
+	times := p.times()
+	if len(times) == 0 {
+		return 0
+	}
+	return seconds(times[len(times)-1])
+}
+
+// Seek sets the position where the next playback starts. The output
+// before it is written at once when the playback starts.
+func (p *Player) Seek(pos time.Duration) {
+	p.start = math.Max(pos.Seconds(), 0)
+}
+
+// Play writes the output of the recording to w (usually os.Stdout) with
+// the recorded timing, starting at the position set with Seek. Input
+// events are skipped. The terminal should have the size of the recording
+// (see Width and Height).
+func (p *Player) Play(w io.Writer) error {
+	return p.play(w, nil)
+}
+
+// PlayTerminal plays the recording like Play on the terminal and lets
+// the user control the playback with these keys:
+//   Space          pause/resume
+//   + and -        double/halve the speed
+//   Right and Left seek forward/backward 5 seconds
+//   q              stop
+// Seeking backward clears the screen and writes the output from the start
+// of the recording at once. It panics if stdin and stdout are not
+// connected to a terminal.
+func (p *Player) PlayTerminal() error {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	checkIsTerminal()
+	restore, err := noEcho(int(inFile.Fd()))
+	if err != nil {
+		return err
+	}
+	defer restore()
+	w := writerFunc(func(b []byte) (int, error) {
+		outMu.Lock()
+		defer outMu.Unlock()
+		out.Write(b)
+		return len(b), out.Flush()
+	})
+	keys := &keyReader{}
+	defer keys.useTypeahead()()
+	return p.play(w, keys)
+}
+
+// ANSI escape codes: cursor home and clear screen (ESC[H ESC[2J).
+const clearScreen = "\x1b[H\x1b[2J"
+
+// seekStep is the time in seconds PlayTerminal seeks with the arrow keys.
+const seekStep = 5
+
+// play plays the recording; if keys is not nil, the playback is
+// controlled with the keys read.
+func (p *Player) play(w io.Writer, keys *keyReader) error {
+	times := p.times()
+	speed := p.Speed
+	if speed <= 0 {
+		speed = 1
+	}
+	pos, paused := p.start, false
+	last := time.Now()
+	for i := 0; i < len(p.events); {
+		now := time.Now()
+		if !paused {
+			pos += now.Sub(last).Seconds() * speed
+		}
+		last = now
+		if times[i] <= pos {
+			if p.events[i].Type == "o" {
+				if _, err := io.WriteString(w, p.events[i].Data); err != nil {
+					return err
+				}
+			}
+			i++
+			continue
+		}
+		timeout := seconds((times[i] - pos) / speed)
+		if keys == nil {
+			time.Sleep(timeout)
+			continue
+		}
+		if paused {
+			timeout = -1
+		}
+		if ok, err := keys.more(timeout); err != nil {
+			return err
+		} else if !ok {
+			continue
+		}
+		for len(keys.pending) > 0 {
+			key, err := keys.readKey()
+			if err != nil {
+				return err
+			}
+			switch string(key) {
+			case " ":
+				paused = !paused
+			case "+":
+				speed = math.Min(speed*2, 64)
+			case "-":
+				speed = math.Max(speed/2, 1.0/64)
+			case "\x1b[C", "\x1bOC":
+				pos += seekStep
+			case "\x1b[D", "\x1bOD":
+				pos = math.Max(pos-seekStep, 0)
+				if _, err := io.WriteString(w, clearScreen); err != nil {
+					return err
+				}
+				i = 0
+			case "q", "Q":
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// seconds converts seconds to a duration.
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+type writerFunc func([]byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
@@ -0,0 +1,57 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// challengeChars are the characters of a challenge; characters that are
+// easily confused (e.g. 0 and O) are not used.
+const challengeChars = "ACEFHKMNPRTUVWXY34679"
+
+// Challenge shows a random string of length characters in large letters
+// and asks the user to type it, e.g. before a destructive operation
+// is run on many files. It returns true if the typed string matches
+// (case is ignored). If prompt is "", MsgChallenge is used. If length
+// is 0, it is 5.
+//   #   # #   # ####
+//   ##  # #   # #   #
+//   # # # # # # ####
+//   #  ## ## ## #  #
+//   #   # #   # #   #
+//   Type the characters above to continue:
+// It panics if stdin and stdout are not connected to a terminal.
+func Challenge(prompt string, length uint) (bool, error) {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	checkIsTerminal()
+	if prompt == "" {
+		prompt = msg(MsgChallenge)
+	}
+	if length == 0 {
+		length = 5
+	}
+	b := make([]byte, length)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(challengeChars))))
+		if err != nil {
+			return false, err
+		}
+		b[i] = challengeChars[n.Int64()]
+	}
+	writeOut(func() {
+		for _, line := range SmallFont.render(string(b)) {
+			fmt.Fprintln(out, line)
+		}
+	})
+	input, _, err := getBytes(prompt, &InputOpt{})
+	writeOut(func() { out.WriteByte(linefeed) })
+	if err != nil {
+		return false, err
+	}
+	return strings.EqualFold(strings.TrimSpace(string(input)), string(b)), nil
+}
@@ -0,0 +1,112 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"math"
+	"strings"
+)
+
+var (
+	sparkChars = []rune("▁▂▃▄▅▆▇█")
+	barChars   = []rune("▏▎▍▌▋▊▉█")
+)
+
+// Sparkline returns a sparkline for the values using block characters
+// (NaN values are shown as spaces). If there are more values than
+// the terminal is wide, only the last values are used.
+func Sparkline(values []float64) string {
+	if width := chartWidth(0); len(values) > width {
+		values = values[len(values)-width:]
+	}
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			min = math.Min(min, v)
+			max = math.Max(max, v)
+		}
+	}
+	var b strings.Builder
+	for _, v := range values {
+		switch {
+		case math.IsNaN(v):
+			b.WriteRune(' ')
+		case max == min:
+			b.WriteRune(sparkChars[len(sparkChars)/2])
+		default:
+			i := int((v - min) / (max - min) * float64(len(sparkChars)-1))
+			b.WriteRune(sparkChars[i])
+		}
+	}
+	return b.String()
+}
+
+// Bar is one bar of a bar chart.
+type Bar struct {
+	Label string
+	Value float64
+}
+
+// BarChartOpt contains the options for the BarChart function.
+type BarChartOpt struct {
+	Width int     // width of the chart; default: width of the terminal
+	Max   float64 // value of a bar with full length; default: maximum value
+	Style Style   // style of the bars
+}
+
+// BarChart returns a horizontal bar chart with one line for each bar:
+// the label, the bar and the value. Negative values are shown as empty
+// bars. The values are formatted with the locale set with SetLocale.
+// opt may be nil.
+//   fmt.Print(term.BarChart([]term.Bar{{"foo", 3}, {"bar", 5.5}}, nil))
+func BarChart(bars []Bar, opt *BarChartOpt) string {
+	if opt == nil {
+		opt = &BarChartOpt{}
+	}
+	labelWidth, valueWidth := 0, 0
+	max := opt.Max
+	values := make([]string, len(bars))
+	for i, bar := range bars {
+		labelWidth = maxInt(labelWidth, StringWidth(bar.Label))
+		values[i] = locale.FormatFloat(bar.Value, -1)
+		valueWidth = maxInt(valueWidth, len(values[i]))
+		if opt.Max <= 0 {
+			max = math.Max(max, bar.Value)
+		}
+	}
+	barWidth := chartWidth(opt.Width) - labelWidth - valueWidth - 2
+	if barWidth < 1 {
+		barWidth = 1
+	}
+	var b strings.Builder
+	for i, bar := range bars {
+		b.WriteString(bar.Label)
+		b.WriteString(strings.Repeat(" ", labelWidth-StringWidth(bar.Label)+1))
+		// length of the bar in eighths of a character
+		n := 0
+		if max > 0 && bar.Value > 0 {
+			n = int(math.Min(bar.Value/max, 1)*float64(barWidth*8) + 0.5)
+		}
+		s := strings.Repeat(string(barChars[7]), n/8)
+		if n%8 > 0 {
+			s += string(barChars[n%8-1])
+		}
+		b.WriteString(opt.Style.Render(s))
+		b.WriteString(strings.Repeat(" ", barWidth-StringWidth(s)+1))
+		b.WriteString(values[i])
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// chartWidth returns width or, if it is 0, the width of the terminal
+// (80 if stdout is not connected to a terminal).
+func chartWidth(width int) int {
+	if width > 0 {
+		return width
+	}
+	if w, _, err := Size(false); err == nil && w > 0 {
+		return int(w)
+	}
+	return 80
+}
@@ -0,0 +1,78 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrCredentialNotFound is returned by a CredentialStore if there is no
+// secret for a service and account.
+var ErrCredentialNotFound = errors.New("credential not found")
+
+// CredentialStore stores secrets, e.g. in the keyring of the user
+// (see SecretService and Keychain, which are only built with the build
+// tag keyring: go build -tags keyring).
+type CredentialStore interface {
+	// Get returns the secret or ErrCredentialNotFound.
+	Get(service, account string) ([]byte, error)
+	// Set saves the secret; an existing secret is replaced.
+	Set(service, account string, secret []byte) error
+}
+
+// CredentialOpt contains the options for SetCredentialStore.
+type CredentialOpt struct {
+	Service   string // e.g. the name of the program
+	Account   string // e.g. the name of the user
+	OfferSave bool   // ask whether a typed password is saved
+}
+
+var (
+	credStore CredentialStore
+	credOpt   CredentialOpt
+)
+
+// SetCredentialStore sets the store that GetPassword consults first: the
+// password is only asked for if the store does not return one (for any
+// reason). If opt.OfferSave is set, the user is asked whether the typed
+// password is saved in the store. nil disables the store.
+//   term.SetCredentialStore(term.SecretService{}, &term.CredentialOpt{
+//       Service:   "myprog",
+//       Account:   user,
+//       OfferSave: true,
+//   })
+func SetCredentialStore(store CredentialStore, opt *CredentialOpt) {
+	credStore = store
+	credOpt = CredentialOpt{}
+	if opt != nil {
+		credOpt = *opt
+	}
+}
+
+// storedPassword returns the password from the credential store (if any).
+func storedPassword() ([]byte, bool) {
+	if credStore == nil {
+		return nil, false
+	}
+	b, err := credStore.Get(credOpt.Service, credOpt.Account)
+	return b, err == nil
+}
+
+// savePassword asks whether the password is saved in the credential store
+// (if OfferSave is set) and saves it.
+func savePassword(b []byte) error {
+	if credStore == nil || !credOpt.OfferSave || len(b) == 0 {
+		return nil
+	}
+	yn := []rune(strings.ToLower(msg(MsgYesNo)))
+	if len(yn) != 2 {
+		yn = []rune("yn")
+	}
+	save, err := yesNo(msg(MsgSavePassword), string(yn[0])+strings.ToUpper(string(yn[1])),
+		&InputOpt{Key: "save-password"})
+	if err != nil || !save {
+		return err
+	}
+	return credStore.Set(credOpt.Service, credOpt.Account, b)
+}
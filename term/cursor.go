@@ -0,0 +1,64 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import "fmt"
+
+// CursorStyle is the shape of the cursor.
+type CursorStyle uint8
+
+const (
+	CursorDefault   CursorStyle = iota // shape configured in the terminal
+	CursorBlock                        // █
+	CursorUnderline                    // _
+	CursorBar                          // |
+)
+
+// ANSI escape code: Set Cursor Style (DECSCUSR: ESC[<n> q)
+// with n 0: default, 1/2: blinking/steady block,
+// 3/4: blinking/steady underline, 5/6: blinking/steady bar.
+func cursorStyleSeq(style CursorStyle, blink bool) string {
+	if style == CursorDefault {
+		return "\x1b[0 q"
+	}
+	n := int(style) * 2
+	if blink {
+		n--
+	}
+	return fmt.Sprintf("\x1b[%d q", n)
+}
+
+// SetCursorStyle sets the shape of the cursor and whether it blinks
+// (blink is ignored for CursorDefault). Terminals that do not support it
+// ignore it. RestoreOnExit resets the shape to the default.
+func SetCursorStyle(style CursorStyle, blink bool) {
+	writeOut(func() {
+		cursorStyle = cursorStyleSeq(style, blink)
+		out.WriteString(cursorStyle)
+	})
+}
+
+// cursorStyle is the escape code of the last call of SetCursorStyle;
+// outMu must be locked when it is used.
+var cursorStyle string
+
+var viCursor bool
+
+// SetViCursor sets whether the input functions show a bar cursor in vi
+// insert mode and a block cursor in vi normal mode (default: false).
+// When the input is finished, the default shape is restored.
+func SetViCursor(b bool) {
+	viCursor = b
+}
+
+// viCursorSeq returns the escape code for the cursor shape of the current
+// vi mode or "" if the shape is not changed.
+func (r *reader) viCursorSeq() string {
+	switch {
+	case !viCursor || !r.vi():
+		return ""
+	case r.viNormal:
+		return cursorStyleSeq(CursorBlock, true)
+	}
+	return cursorStyleSeq(CursorBar, true)
+}
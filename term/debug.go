@@ -0,0 +1,233 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/andreas19/go-term/term/ansi"
+	"golang.org/x/sys/unix"
+)
+
+// ANSI escape code: Device Status Report (cursor position) ESC[6n;
+// response: ESC[<row>;<column>R.
+const cursorPosQuery = "\x1b[6n"
+
+// debugModes are the DEC private modes that DebugState queries.
+var debugModes = []struct {
+	name string
+	mode int
+}{
+	{"appcursor", 1}, {"autowrap", 7}, {"cursor", 25}, {"mouse", 1000},
+	{"mouse-drag", 1002}, {"mouse-motion", 1003}, {"focus", 1004},
+	{"mouse-sgr", 1006}, {"altscreen", 1049}, {"paste", 2004}, {"sync", 2026},
+}
+
+// recentOutputSize is the number of bytes of the output kept for DebugState.
+const recentOutputSize = 4096
+
+// recentWriter keeps the last bytes written to the terminal by the functions
+// of this package; outMu must be locked when it is used.
+type recentWriter struct {
+	w       io.Writer
+	buf     []byte
+	written int64
+}
+
+func (rw *recentWriter) Write(p []byte) (int, error) {
+	n, err := rw.w.Write(p)
+	rw.buf = append(rw.buf, p[:n]...)
+	if len(rw.buf) > recentOutputSize {
+		copy(rw.buf, rw.buf[len(rw.buf)-recentOutputSize:])
+		rw.buf = rw.buf[:recentOutputSize]
+	}
+	rw.written += int64(n)
+	return n, err
+}
+
+var recentOutput recentWriter
+
+// TermState is a snapshot of the state of the terminal for debugging
+// (see DebugState).
+type TermState struct {
+	Time   time.Time
+	Device string
+	Width  int
+	Height int
+	// the termios flags (e.g. "ECHO") and whether they are set
+	Flags map[string]bool
+	// the termios special characters in caret notation (e.g. "VINTR": "^C")
+	Chars map[string]string
+	// canonical mode and echo are disabled
+	Raw bool
+	// the position of the cursor (starting with 1); 0 if unknown
+	CursorRow int
+	CursorCol int
+	// the DEC private modes reported by the terminal (e.g. "altscreen",
+	// "mouse" or "cursor"); modes the terminal does not report are missing
+	Modes map[string]bool
+	// the last bytes written to the terminal by the functions of this package
+	Output []byte
+	// the number of bytes written before Output was taken and after
+	// the queries of DebugState
+	written, writtenAfter int64
+}
+
+// DebugState returns a snapshot of the state of the terminal: the termios
+// flags, the cursor position, the active modes (raw mode, alternate screen,
+// mouse tracking etc.) and the recent output of this package. It helps to
+// find out what a program left behind if the terminal is "broken" after it
+// ran:
+//   before, _ := term.DebugState()
+//   ...
+//   after, _ := term.DebugState()
+//   fmt.Fprint(os.Stderr, after.Diff(before))
+// The cursor position and the modes are queried from the terminal (see
+// SetQueryTimeout) if stdin is connected to a terminal, too; they are
+// unknown if the terminal does not respond. It returns an error if stdout
+// is not connected to a terminal.
+func DebugState() (*TermState, error) {
+	outMu.Lock()
+	f := outFile
+	s := &TermState{
+		Time:    time.Now(),
+		Output:  append([]byte(nil), recentOutput.buf...),
+		written: recentOutput.written,
+	}
+	s.writtenAfter = s.written
+	outMu.Unlock()
+	fd := int(f.Fd())
+	termios, err := unix.IoctlGetTermios(fd, termiosGet)
+	if err != nil {
+		return nil, err
+	}
+	if s.Device, err = TTYName(f.Fd()); err != nil {
+		s.Device = "?"
+	}
+	if ws, err := unix.IoctlGetWinsize(fd, unix.TIOCGWINSZ); err == nil {
+		s.Width, s.Height = int(ws.Col), int(ws.Row)
+	}
+	s.Flags = map[string]bool{}
+	for _, fl := range []struct {
+		flags uint64
+		names []flagName
+	}{
+		{uint64(termios.Iflag), iflagNames}, {uint64(termios.Oflag), oflagNames},
+		{uint64(termios.Cflag), cflagNames}, {uint64(termios.Lflag), lflagNames},
+	} {
+		for _, n := range fl.names {
+			s.Flags[n.name] = fl.flags&n.bit != 0
+		}
+	}
+	s.Chars = map[string]string{}
+	for _, c := range ccNames {
+		s.Chars[c.name] = ccString(termios.Cc[c.index])
+	}
+	s.Raw = termios.Lflag&(unix.ICANON|unix.ECHO) == 0
+	s.Modes = map[string]bool{}
+	if IsTerminal(inFile.Fd()) && IsTerminal(f.Fd()) {
+		s.queryTerminal()
+		// the queries are not part of the output for Diff
+		outMu.Lock()
+		s.writtenAfter = recentOutput.written
+		outMu.Unlock()
+	}
+	return s, nil
+}
+
+// queryTerminal queries the cursor position and the modes.
+func (s *TermState) queryTerminal() {
+	var b strings.Builder
+	b.WriteString(cursorPosQuery)
+	for _, m := range debugModes {
+		fmt.Fprintf(&b, requestMode, m.mode)
+	}
+	resp, _ := queryDA(b.String())
+	var p ansi.Parser
+	for _, seq := range p.Feed(resp) {
+		switch {
+		case seq.Type != ansi.CSI:
+		case seq.Prefix == 0 && seq.Final == 'R':
+			s.CursorRow, s.CursorCol = seq.Param(0, 1), seq.Param(1, 1)
+		case seq.Prefix == '?' && seq.Final == 'y':
+			// value 0: not recognized, 1/3: (permanently) set,
+			// 2/4: (permanently) reset
+			for _, m := range debugModes {
+				if v := seq.Param(1, 0); seq.Param(0, 0) == m.mode && v != 0 {
+					s.Modes[m.name] = v == 1 || v == 3
+				}
+			}
+		}
+	}
+}
+
+// lines returns the state as lines of name and value.
+func (s *TermState) lines() [][2]string {
+	onOff := func(b bool) string {
+		if b {
+			return "on"
+		}
+		return "off"
+	}
+	cursor := "?"
+	if s.CursorRow > 0 {
+		cursor = fmt.Sprintf("%d;%d", s.CursorRow, s.CursorCol)
+	}
+	lines := [][2]string{
+		{"device", s.Device},
+		{"size", fmt.Sprintf("%dx%d", s.Width, s.Height)},
+		{"raw", onOff(s.Raw)},
+		{"cursor", cursor},
+	}
+	for _, m := range debugModes {
+		v := "?"
+		if b, ok := s.Modes[m.name]; ok {
+			v = onOff(b)
+		}
+		lines = append(lines, [2]string{"mode " + m.name, v})
+	}
+	for _, names := range [][]flagName{iflagNames, oflagNames, cflagNames, lflagNames} {
+		for _, n := range names {
+			lines = append(lines, [2]string{"flag " + n.name, onOff(s.Flags[n.name])})
+		}
+	}
+	for _, c := range ccNames {
+		lines = append(lines, [2]string{"char " + c.name, s.Chars[c.name]})
+	}
+	return lines
+}
+
+// String returns the state with one line per value.
+func (s *TermState) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "time: %s\n", s.Time.Format("15:04:05.000"))
+	for _, l := range s.lines() {
+		fmt.Fprintf(&b, "%s: %s\n", l[0], l[1])
+	}
+	fmt.Fprintf(&b, "output: %q\n", s.Output)
+	return b.String()
+}
+
+// Diff returns the differences between an older snapshot old and s,
+// one line per value (e.g. "mode altscreen: off -> on"), and the output
+// written since old was taken.
+func (s *TermState) Diff(old *TermState) string {
+	var b strings.Builder
+	oldLines := old.lines()
+	for i, l := range s.lines() {
+		if o := oldLines[i]; o[1] != l[1] {
+			fmt.Fprintf(&b, "%s: %s -> %s\n", l[0], o[1], l[1])
+		}
+	}
+	if n := s.written - old.writtenAfter; n > 0 {
+		if n > int64(len(s.Output)) {
+			fmt.Fprintf(&b, "output: ...%q\n", s.Output)
+		} else {
+			fmt.Fprintf(&b, "output: %q\n", s.Output[int64(len(s.Output))-n:])
+		}
+	}
+	return b.String()
+}
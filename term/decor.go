@@ -0,0 +1,123 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Decorations are printed around the prompts of Input and the functions
+// based on it (see SetDecorations).
+//
+// FinalRender is called after an input was accepted with the prompt and
+// the value as it was echoed (masked or empty depending on InputOpt.Echo;
+// the default value if the input was empty; redacted if InputOpt.Redact
+// is set); the line of the prompt is
+// replaced with the returned string. By default the line is replaced with
+// the Success icon, the prompt and the value.
+type Decorations struct {
+	Prefix      string                            // printed before the prompt (Theme.Accent)
+	Success     string                            // replaces Prefix after the input was accepted (Theme.Success)
+	Failure     string                            // replaces Prefix if the input failed or was canceled (Theme.Error)
+	FinalRender func(prompt, value string) string // optional
+}
+
+// SurveyDecorations decorate the prompts with a question mark and
+// the result with a check mark or a cross:
+//   ? Name: _
+//   ✔ Name: Alice
+var SurveyDecorations = Decorations{Prefix: "? ", Success: "✔ ", Failure: "✖ "}
+
+var decorations Decorations
+
+// SetDecorations sets the decorations of the prompts; by default there are
+// none and the prompt line is not changed after the input.
+//   term.SetDecorations(term.SurveyDecorations)
+func SetDecorations(d Decorations) {
+	decorations = d
+}
+
+// decorated reports whether decorations are set.
+func decorated() bool {
+	d := decorations
+	return d.Prefix != "" || d.Success != "" || d.Failure != "" || d.FinalRender != nil
+}
+
+// decoratePrompt returns the prompt with the Prefix icon.
+func decoratePrompt(prompt string) string {
+	return theme.Accent.Render(decorations.Prefix) + prompt
+}
+
+// echoed returns s as it is echoed with the mode.
+func echoed(s string, mode EchoMode) string {
+	var echo strings.Builder
+	lb := NewLineBuffer(&echo, mode)
+	lb.echo([]byte(s))
+	return echo.String()
+}
+
+// Redaction selects what replaces an input after it was accepted
+// (see InputOpt.Redact and SetPasswordRedaction).
+type Redaction uint8
+
+const (
+	RedactNone   Redaction = iota // the input stays as it was echoed
+	RedactMask                    // "********" (always 8 characters, so the length is not revealed)
+	RedactHidden                  // "(hidden)" (message MsgHidden)
+)
+
+// redact returns the text that replaces the input s.
+func (r Redaction) redact(s string) string {
+	switch r {
+	case RedactMask:
+		return strings.Repeat(string(maskChar), 8)
+	case RedactHidden:
+		return msg(MsgHidden)
+	}
+	return s
+}
+
+var passwordRedaction Redaction
+
+// SetPasswordRedaction sets what replaces the masked input of GetPassword
+// after Enter was typed (default: RedactNone):
+//   term.SetPasswordRedaction(term.RedactHidden)
+//   fmt.Print("Password: ")
+//   pw, err := term.GetPassword() // -> Password: (hidden)
+func SetPasswordRedaction(r Redaction) {
+	passwordRedaction = r
+}
+
+// finishPrompt replaces the line of the prompt of the last input, which was
+// ended with a newline, with the final rendering. s is the input (empty if
+// the default value was used).
+func finishPrompt(prompt string, opt *InputOpt, s string, err error) {
+	if !decorated() && (opt.Redact == RedactNone || err != nil) || !IsTerminal(outFile.Fd()) {
+		return
+	}
+	var line string
+	switch {
+	case err != nil:
+		line = theme.Error.Render(decorations.Failure) + prompt
+	default:
+		value := echoed(s, opt.Echo)
+		if s == "" && opt.Default != nil {
+			value = fmt.Sprint(opt.Default)
+		}
+		if opt.Redact != RedactNone {
+			value = opt.Redact.redact(value)
+		}
+		if decorations.FinalRender != nil {
+			line = decorations.FinalRender(prompt, value)
+		} else {
+			line = theme.Success.Render(decorations.Success) + prompt
+			if decorated() {
+				value = theme.Accent.Render(value)
+			}
+			line += value
+		}
+	}
+	resetPrompt()
+	writeOut(func() { fmt.Fprintln(out, line) })
+}
@@ -0,0 +1,37 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import "strings"
+
+// Definition returns the pairs of labels and values as aligned
+// "Label: value" lines, e.g. for showing a configuration. Values that are
+// longer than the width of the terminal (80 if stdout is not connected to
+// a terminal) are wrapped at spaces and continued under the value column.
+//   fmt.Print(term.Definition([][2]string{
+//       {"Name", "example"},
+//       {"Description", "a long text ..."},
+//   }))
+//   ->
+//   Name:        example
+//   Description: a long text ...
+func Definition(pairs [][2]string) string {
+	labelWidth := 0
+	for _, p := range pairs {
+		labelWidth = maxInt(labelWidth, StringWidth(p[0]))
+	}
+	indent := labelWidth + 2
+	valueWidth := chartWidth(0) - indent
+	var b strings.Builder
+	for _, p := range pairs {
+		b.WriteString(p[0] + ":")
+		pad := strings.Repeat(" ", labelWidth-StringWidth(p[0])+1)
+		for i, line := range wrapText(p[1], valueWidth) {
+			if i > 0 {
+				pad = strings.Repeat(" ", indent)
+			}
+			b.WriteString(strings.TrimRight(pad+line, " ") + "\n")
+		}
+	}
+	return b.String()
+}
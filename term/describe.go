@@ -0,0 +1,149 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// TTYName returns the path of the terminal device the file descriptor fd
+// is connected to (e.g. "/dev/pts/3"). It returns an error if fd is not
+// connected to a terminal.
+func TTYName(fd uintptr) (string, error) {
+	if !IsTerminal(fd) {
+		return "", ErrNotATerminal
+	}
+	var st unix.Stat_t
+	if err := unix.Fstat(int(fd), &st); err != nil {
+		return "", err
+	}
+	isDevice := func(name string) bool {
+		var dst unix.Stat_t
+		return unix.Stat(name, &dst) == nil && dst.Mode&unix.S_IFMT == unix.S_IFCHR &&
+			dst.Rdev == st.Rdev
+	}
+	// Linux
+	if name, err := os.Readlink("/proc/self/fd/" + strconv.Itoa(int(fd))); err == nil && isDevice(name) {
+		return name, nil
+	}
+	for _, dir := range []string{"/dev/pts", "/dev"} {
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, fi := range files {
+			if name := filepath.Join(dir, fi.Name()); fi.Mode()&os.ModeCharDevice != 0 && isDevice(name) {
+				return name, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("terminal device not found")
+}
+
+type flagName struct {
+	name string
+	bit  uint64
+}
+
+var (
+	iflagNames = []flagName{
+		{"IGNBRK", unix.IGNBRK}, {"BRKINT", unix.BRKINT}, {"IGNPAR", unix.IGNPAR},
+		{"PARMRK", unix.PARMRK}, {"INPCK", unix.INPCK}, {"ISTRIP", unix.ISTRIP},
+		{"INLCR", unix.INLCR}, {"IGNCR", unix.IGNCR}, {"ICRNL", unix.ICRNL},
+		{"IXON", unix.IXON}, {"IXANY", unix.IXANY}, {"IXOFF", unix.IXOFF},
+		{"IMAXBEL", unix.IMAXBEL},
+	}
+	oflagNames = []flagName{
+		{"OPOST", unix.OPOST}, {"ONLCR", unix.ONLCR},
+	}
+	cflagNames = []flagName{
+		{"CSTOPB", unix.CSTOPB}, {"CREAD", unix.CREAD}, {"PARENB", unix.PARENB},
+		{"PARODD", unix.PARODD}, {"HUPCL", unix.HUPCL}, {"CLOCAL", unix.CLOCAL},
+	}
+	lflagNames = []flagName{
+		{"ISIG", unix.ISIG}, {"ICANON", unix.ICANON}, {"ECHO", unix.ECHO},
+		{"ECHOE", unix.ECHOE}, {"ECHOK", unix.ECHOK}, {"ECHONL", unix.ECHONL},
+		{"NOFLSH", unix.NOFLSH}, {"TOSTOP", unix.TOSTOP}, {"IEXTEN", unix.IEXTEN},
+		{"ECHOCTL", unix.ECHOCTL}, {"ECHOKE", unix.ECHOKE},
+	}
+	ccNames = []struct {
+		name  string
+		index int
+	}{
+		{"VINTR", unix.VINTR}, {"VQUIT", unix.VQUIT}, {"VERASE", unix.VERASE},
+		{"VKILL", unix.VKILL}, {"VEOF", unix.VEOF}, {"VSTART", unix.VSTART},
+		{"VSTOP", unix.VSTOP}, {"VSUSP", unix.VSUSP},
+	}
+)
+
+// flagString returns the names of the flags in names; set flags are
+// prefixed with '+', cleared flags with '-'.
+func flagString(flags uint64, names []flagName) string {
+	s := make([]string, len(names))
+	for i, f := range names {
+		if flags&f.bit != 0 {
+			s[i] = "+" + f.name
+		} else {
+			s[i] = "-" + f.name
+		}
+	}
+	return strings.Join(s, " ")
+}
+
+// ccString returns a special character in caret notation.
+func ccString(c byte) string {
+	switch {
+	case c == 0 || c == 0xFF:
+		return "<undef>"
+	case c < 0x20:
+		return "^" + string(rune(c+'@'))
+	case c == 0x7F:
+		return "^?"
+	}
+	return string(rune(c))
+}
+
+// Describe returns a description of the terminal the file descriptor fd is
+// connected to for diagnostics: the device, the window size, the value of
+// TERM and the termios flags and special characters, e.g.:
+//   device: /dev/pts/3
+//   size:   80x24 (800x480 pixels)
+//   TERM:   xterm-256color
+//   iflag:  -IGNBRK +BRKINT ...
+//   ...
+//   cc:     VINTR=^C VQUIT=^\ ...
+// It returns an error if fd is not connected to a terminal.
+func Describe(fd uintptr) (string, error) {
+	termios, err := unix.IoctlGetTermios(int(fd), termiosGet)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	name, err := TTYName(fd)
+	if err != nil {
+		name = "?"
+	}
+	fmt.Fprintf(&b, "device: %s\n", name)
+	if ws, err := unix.IoctlGetWinsize(int(fd), unix.TIOCGWINSZ); err == nil {
+		fmt.Fprintf(&b, "size:   %dx%d (%dx%d pixels)\n", ws.Col, ws.Row, ws.Xpixel, ws.Ypixel)
+	}
+	fmt.Fprintf(&b, "TERM:   %s\n", os.Getenv("TERM"))
+	fmt.Fprintf(&b, "iflag:  %s\n", flagString(uint64(termios.Iflag), iflagNames))
+	fmt.Fprintf(&b, "oflag:  %s\n", flagString(uint64(termios.Oflag), oflagNames))
+	fmt.Fprintf(&b, "cflag:  %s\n", flagString(uint64(termios.Cflag), cflagNames))
+	fmt.Fprintf(&b, "lflag:  %s\n", flagString(uint64(termios.Lflag), lflagNames))
+	cc := make([]string, len(ccNames))
+	for i, c := range ccNames {
+		cc[i] = c.name + "=" + ccString(termios.Cc[c.index])
+	}
+	fmt.Fprintf(&b, "cc:     %s VMIN=%d VTIME=%d\n", strings.Join(cc, " "),
+		termios.Cc[unix.VMIN], termios.Cc[unix.VTIME])
+	return b.String(), nil
+}
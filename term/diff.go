@@ -0,0 +1,219 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DiffOpt contains the options for the Diff function.
+type DiffOpt struct {
+	SideBySide bool  // show the old and the new lines side by side
+	Width      int   // width for SideBySide; default: width of the terminal
+	Context    int   // number of unchanged lines around changes; default: 3
+	AllLines   bool  // show all unchanged lines
+	DelStyle   Style // style of deleted lines; default: Theme.Error
+	AddStyle   Style // style of added lines; default: Theme.Success
+}
+
+// diffOp is a line of an edit script: ' ' (unchanged), '-' (deleted)
+// or '+' (added).
+type diffOp struct {
+	kind byte
+	line string
+	a, b int // line numbers in a and b (starting with 0)
+}
+
+// Diff returns the differences between the lines of a and b in
+// the unified format (without file headers) or side by side, e.g. for
+// showing changes before they are confirmed. Deleted and added lines are
+// colored. It returns "" if a and b are equal. opt may be nil.
+//   fmt.Print(term.Diff(oldConfig, newConfig, nil))
+func Diff(a, b string, opt *DiffOpt) string {
+	if opt == nil {
+		opt = &DiffOpt{}
+	}
+	if a == b {
+		return ""
+	}
+	ops := diffLines(splitLines(a), splitLines(b))
+	context := opt.Context
+	if context <= 0 {
+		context = 3
+	}
+	if opt.AllLines {
+		context = len(ops)
+	}
+	delStyle, addStyle := opt.DelStyle, opt.AddStyle
+	if delStyle == (Style{}) {
+		delStyle = theme.Error
+	}
+	if addStyle == (Style{}) {
+		addStyle = theme.Success
+	}
+	var sb strings.Builder
+	for _, h := range diffHunks(ops, context) {
+		if opt.SideBySide {
+			sideBySide(&sb, h, chartWidth(opt.Width), delStyle, addStyle)
+		} else {
+			unified(&sb, h, delStyle, addStyle)
+		}
+	}
+	return sb.String()
+}
+
+// splitLines splits s into lines; a final newline does not start
+// another line.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines returns the edit script that changes a into b using
+// the longest common subsequence of the lines.
+func diffLines(a, b []string) []diffOp {
+	// lcs[i][j] is the length of the LCS of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = maxInt(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i], i, j})
+			i++
+			j++
+		case j == len(b) || i < len(a) && lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i], i, j})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j], i, j})
+			j++
+		}
+	}
+	return ops
+}
+
+// diffHunks groups the changes with the given number of unchanged lines
+// around them.
+func diffHunks(ops []diffOp, context int) [][]diffOp {
+	var hunks [][]diffOp
+	start, end := -1, -1
+	for i, op := range ops {
+		if op.kind == ' ' {
+			continue
+		}
+		if start >= 0 && i-context <= end {
+			end = minInt(i+context, len(ops)-1)
+			continue
+		}
+		if start >= 0 {
+			hunks = append(hunks, ops[start:end+1])
+		}
+		start, end = maxInt(i-context, 0), minInt(i+context, len(ops)-1)
+	}
+	if start >= 0 {
+		hunks = append(hunks, ops[start:end+1])
+	}
+	return hunks
+}
+
+// unified writes a hunk in the unified format.
+func unified(sb *strings.Builder, h []diffOp, delStyle, addStyle Style) {
+	var na, nb int
+	for _, op := range h {
+		if op.kind != '+' {
+			na++
+		}
+		if op.kind != '-' {
+			nb++
+		}
+	}
+	fmt.Fprintf(sb, "@@ -%s +%s @@\n", hunkRange(h[0].a, na), hunkRange(h[0].b, nb))
+	for _, op := range h {
+		line := string(op.kind) + op.line
+		switch op.kind {
+		case '-':
+			line = delStyle.Render(line)
+		case '+':
+			line = addStyle.Render(line)
+		}
+		sb.WriteString(line + "\n")
+	}
+}
+
+// hunkRange returns the range of a hunk header (start with 1).
+func hunkRange(start, n int) string {
+	if n == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if n == 1 {
+		return fmt.Sprint(start + 1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, n)
+}
+
+// sideBySide writes a hunk with the old lines on the left and the new
+// lines on the right; deleted and added lines of a change are paired.
+func sideBySide(sb *strings.Builder, h []diffOp, width int, delStyle, addStyle Style) {
+	col := maxInt((width-3)/2, 1)
+	cell := func(s string, style Style) string {
+		s = truncate(s, col)
+		return style.Render(s) + strings.Repeat(" ", col-StringWidth(s))
+	}
+	fmt.Fprintf(sb, "@@ %d,%d @@\n", h[0].a+1, h[0].b+1)
+	for i := 0; i < len(h); {
+		if h[i].kind == ' ' {
+			sb.WriteString(strings.TrimRight(cell(h[i].line, Style{})+"   "+truncate(h[i].line, col), " ") + "\n")
+			i++
+			continue
+		}
+		var del, add []string
+		for ; i < len(h) && h[i].kind == '-'; i++ {
+			del = append(del, h[i].line)
+		}
+		for ; i < len(h) && h[i].kind == '+'; i++ {
+			add = append(add, h[i].line)
+		}
+		for k := 0; k < maxInt(len(del), len(add)); k++ {
+			var left, right, sep string
+			switch {
+			case k < len(del) && k < len(add):
+				left, right, sep = cell(del[k], delStyle), addStyle.Render(truncate(add[k], col)), " | "
+			case k < len(del):
+				left, sep = cell(del[k], delStyle), " <"
+			default:
+				left, right, sep = strings.Repeat(" ", col), addStyle.Render(truncate(add[k], col)), " > "
+			}
+			sb.WriteString(left + sep + right + "\n")
+		}
+	}
+}
+
+// truncate returns the grapheme clusters of s that fit into width columns.
+func truncate(s string, width int) string {
+	b := []byte(s)
+	n := 0
+	for n < len(b) {
+		l := graphemeLen(b[n:])
+		if bytesWidth(b[:n+l]) > width {
+			break
+		}
+		n += l
+	}
+	return s[:n]
+}
@@ -7,6 +7,7 @@ ANSI escape sequences.
 
 It is only tested on Linux with the Xfce terminal emulator and the Linux console.
 
-All inputs can be canceled with ^D (EOF).
+All inputs can be canceled with ^D (EOF); the input functions return ErrEOF
+in this case.
 */
 package term
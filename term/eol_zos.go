@@ -0,0 +1,9 @@
+// +build zos
+
+package term
+
+import "golang.org/x/sys/unix"
+
+// eolChars are the indexes of the additional line delimiters
+// in the special characters of the terminal.
+var eolChars = []int{unix.VEOL}
@@ -0,0 +1,117 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// ANSI escape codes: enable/disable focus reporting (ESC[?1004h/l),
+//                    focus in/out (ESC[I and ESC[O).
+const (
+	focusEventsOn  = "\x1b[?1004h"
+	focusEventsOff = "\x1b[?1004l"
+	focusIn        = "\x1b[I"
+	focusOut       = "\x1b[O"
+)
+
+// EventType is the type of an input event.
+type EventType uint8
+
+const (
+	EventKey    EventType = iota // a key was typed
+	FocusGained                  // the terminal window got the focus
+	FocusLost                    // the terminal window lost the focus
+	Resized                      // the size of the window changed (see EnableResizeEvents)
+)
+
+// Event is an input event read by ReadEvent. For EventKey, Code and Mod
+// are decoded from Key: Code is the rune or one of the Key... constants
+// (0 for unknown escape sequences). In the traditional encoding control
+// characters are not decoded (^I is '\t'); see EnableKeyboardEnhancement.
+type Event struct {
+	Type    EventType
+	Key     []byte    // the key (a rune or an escape sequence) for EventKey
+	Code    rune      // the decoded key
+	Mod     Modifiers // the decoded modifiers
+	Repeat  bool      // the key is held down (kitty protocol)
+	Release bool      // the key was released (kitty protocol)
+	Width   uint16    // the new width for Resized
+	Height  uint16    // the new height for Resized
+}
+
+// EnableFocusEvents sets whether the terminal reports when its window
+// gets or loses the focus (FocusGained and FocusLost events). Terminals
+// that do not support it ignore it. RestoreOnExit disables it.
+func EnableFocusEvents(b bool) {
+	writeOut(func() {
+		if b {
+			out.WriteString(focusEventsOn)
+		} else {
+			out.WriteString(focusEventsOff)
+		}
+	})
+}
+
+var focusFunc func(focused bool)
+
+// SetFocusFunc sets a function that is called when a focus event is read
+// while an input function is running (nil removes it); otherwise focus
+// events are ignored by the input functions. The function is called
+// from the goroutine of the input function and must not print anything.
+func SetFocusFunc(f func(focused bool)) {
+	focusFunc = f
+}
+
+// focusEvent returns the type of the event if key is a focus event.
+func focusEvent(key []byte) (EventType, bool) {
+	switch string(key) {
+	case focusIn:
+		return FocusGained, true
+	case focusOut:
+		return FocusLost, true
+	}
+	return EventKey, false
+}
+
+var (
+	eventMu     sync.Mutex
+	eventReader keyReader
+)
+
+// ReadEvent waits for the next key, focus or resize event. Echo and canonical
+// mode are disabled while waiting, but the terminal should be in raw mode
+// (see MakeRaw) if events are read continuously, so that keys typed
+// between two calls are not echoed. It panics if stdin and stdout are not
+// connected to a terminal.
+func ReadEvent() (Event, error) {
+	eventMu.Lock()
+	defer eventMu.Unlock()
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	checkIsTerminal()
+	restore, err := noEcho(int(inFile.Fd()))
+	if err != nil {
+		return Event{}, err
+	}
+	defer restore()
+	defer eventReader.useTypeahead()()
+	eventReader.resize = atomic.LoadInt32(&resizeEvents) != 0
+	key, err := eventReader.readKey()
+	if err == errResized {
+		waitResize()
+		ev := Event{Type: Resized}
+		ev.Width, ev.Height, err = Size(true)
+		return ev, err
+	}
+	if err != nil {
+		return Event{}, err
+	}
+	if typ, ok := focusEvent(key); ok {
+		return Event{Type: typ}, nil
+	}
+	ev := Event{Type: EventKey, Key: key}
+	ev.decodeKey()
+	return ev, nil
+}
@@ -0,0 +1,233 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/andreas19/go-term/term/ansi"
+)
+
+// ColorDepth is the number of colors a ColorWriter converts colors to.
+type ColorDepth uint8
+
+const (
+	ColorsNone ColorDepth = iota // colors are removed (other attributes are kept)
+	Colors16                     // the 16 standard colors
+	Colors256                    // the 256 color palette
+	ColorsTrue                   // true colors (the colors are not changed)
+)
+
+type filterWriter struct {
+	w      io.Writer
+	p      ansi.Parser
+	strip  bool
+	colors ColorDepth
+}
+
+func (fw *filterWriter) Write(p []byte) (int, error) {
+	var b strings.Builder
+	for _, seq := range fw.p.Feed(p) {
+		switch {
+		case seq.Type == ansi.Text:
+			b.Write(seq.Data)
+		case seq.Type == ansi.Control:
+			if !fw.strip || seq.Data[0] == '\n' || seq.Data[0] == '\r' || seq.Data[0] == '\t' {
+				b.Write(seq.Data)
+			}
+		case fw.strip:
+		case seq.Type == ansi.CSI && seq.Final == 'm' && seq.Prefix == 0 &&
+			len(seq.Intermediate) == 0 && fw.colors != ColorsTrue:
+			if params := convertSGR(seq.Params, fw.colors); params != "" || len(seq.Params) == 0 {
+				b.WriteString("\x1b[" + params + "m")
+			}
+		default:
+			b.WriteString(seq.String())
+		}
+	}
+	if _, err := io.WriteString(fw.w, b.String()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// StripWriter returns a writer that removes all escape sequences and
+// control characters except newline, carriage return and tab from
+// the output before it is written to w, e.g. for writing output styled
+// with Style to a log file. An incomplete escape sequence at the end of
+// a write is kept until the next write.
+func StripWriter(w io.Writer) io.Writer {
+	return &filterWriter{w: w, strip: true}
+}
+
+// ColorWriter returns a writer that converts the colors in the output
+// to the color depth before it is written to w, e.g. for terminals that
+// do not support true colors. Colors are replaced by the nearest color of
+// the default xterm palette. An incomplete escape sequence at the end of
+// a write is kept until the next write.
+func ColorWriter(w io.Writer, depth ColorDepth) io.Writer {
+	return &filterWriter{w: w, colors: depth}
+}
+
+// convertSGR returns the SGR parameters with the colors converted.
+// The result is empty if nothing is left.
+func convertSGR(params [][]int, depth ColorDepth) string {
+	var out []string
+	for i := 0; i < len(params); i++ {
+		p := params[i][0]
+		if p < 0 {
+			p = 0
+		}
+		switch {
+		case p == 38 || p == 48 || p == 58:
+			// extended color as sub-parameters (38:5:n, 38:2::r:g:b) or
+			// as parameters (38;5;n, 38;2;r;g;b)
+			args := params[i][1:]
+			if len(args) == 0 {
+				for _, q := range params[i+1:] {
+					args = append(args, q[0])
+				}
+				switch {
+				case len(args) >= 2 && args[0] == 5:
+					args = args[:2]
+				case len(args) >= 4 && args[0] == 2:
+					args = args[:4]
+				default:
+					args = nil
+				}
+				i += len(args)
+			} else if len(args) == 5 && args[0] == 2 {
+				// drop the color space id
+				args = append([]int{2}, args[2:]...)
+			}
+			if c, ok := extendedColor(args); ok {
+				out = append(out, convertColor(c, p, depth)...)
+			}
+		case p >= 30 && p <= 39 || p >= 40 && p <= 49 || p >= 90 && p <= 97 ||
+			p >= 100 && p <= 107 || p == 59:
+			if depth != ColorsNone {
+				out = append(out, strconv.Itoa(p))
+			}
+		default:
+			out = append(out, strconv.Itoa(p))
+		}
+	}
+	return strings.Join(out, ";")
+}
+
+// extendedColor returns the color for the arguments of SGR 38, 48 and 58.
+func extendedColor(args []int) (Color, bool) {
+	valid := func(n int) bool { return n >= 0 && n <= 255 }
+	switch {
+	case len(args) == 2 && args[0] == 5 && valid(args[1]):
+		return Index(uint8(args[1])), true
+	case len(args) == 4 && args[0] == 2 && valid(args[1]) && valid(args[2]) && valid(args[3]):
+		return RGB(uint8(args[1]), uint8(args[2]), uint8(args[3])), true
+	}
+	return 0, false
+}
+
+// convertColor returns the SGR parameters for the color converted to
+// the color depth; base is 38, 48 or 58 (underline color).
+func convertColor(c Color, base int, depth ColorDepth) []string {
+	n := int(c & 0xFF)
+	switch {
+	case depth == ColorsNone:
+		return nil
+	case depth == ColorsTrue && c&^0xFFFFFF == colorRGB:
+		return strings.Split(c.sgr(base), ";")
+	case depth >= Colors256:
+		if c&^0xFFFFFF == colorRGB {
+			n = int(nearest256(c.rgb()))
+		}
+		return []string{strconv.Itoa(base), "5", strconv.Itoa(n)}
+	case base == 58:
+		// there is no parameter for the underline color with 16 colors
+		return nil
+	}
+	if c&^0xFFFFFF != colorIndexed || n >= 16 {
+		n = int(nearest16(c.rgb()))
+	}
+	if n >= 8 {
+		return []string{strconv.Itoa(base + 52 + n - 8)}
+	}
+	return []string{strconv.Itoa(base - 8 + n)}
+}
+
+// ansi16 are the colors of the default xterm palette with index 0-15.
+var ansi16 = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// cubeLevels are the levels of the 6x6x6 color cube (index 16-231).
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// rgb returns the components of the color; indexed colors are looked up
+// in the default xterm palette.
+func (c Color) rgb() (r, g, b uint8) {
+	if c&^0xFFFFFF == colorRGB {
+		return uint8(c >> 16), uint8(c >> 8), uint8(c)
+	}
+	n := int(c & 0xFF)
+	switch {
+	case n < 16:
+		return ansi16[n][0], ansi16[n][1], ansi16[n][2]
+	case n < 232:
+		n -= 16
+		return cubeLevels[n/36], cubeLevels[n/6%6], cubeLevels[n%6]
+	}
+	v := uint8(8 + (n-232)*10)
+	return v, v, v
+}
+
+// nearest256 returns the index of the color of the color cube or
+// the gray ramp (index 16-255) that is nearest to the given color.
+func nearest256(r, g, b uint8) uint8 {
+	level := func(v uint8) int {
+		switch {
+		case v < 48:
+			return 0
+		case v < 115:
+			return 1
+		}
+		return (int(v) - 35) / 40
+	}
+	cube := 16 + 36*level(r) + 6*level(g) + level(b)
+	avg := (int(r) + int(g) + int(b)) / 3
+	gray := 232
+	if avg > 8 {
+		gray += (avg - 3) / 10
+		if gray > 255 {
+			gray = 255
+		}
+	}
+	cr, cg, cb := Index(uint8(cube)).rgb()
+	gr, gg, gb := Index(uint8(gray)).rgb()
+	if colorDist(r, g, b, gr, gg, gb) < colorDist(r, g, b, cr, cg, cb) {
+		return uint8(gray)
+	}
+	return uint8(cube)
+}
+
+// nearest16 returns the index of the standard color (index 0-15) that is
+// nearest to the given color.
+func nearest16(r, g, b uint8) uint8 {
+	best := 0
+	for i, c := range ansi16 {
+		if colorDist(r, g, b, c[0], c[1], c[2]) < colorDist(r, g, b, ansi16[best][0], ansi16[best][1], ansi16[best][2]) {
+			best = i
+		}
+	}
+	return uint8(best)
+}
+
+// colorDist returns the squared euclidean distance of two colors.
+func colorDist(r1, g1, b1, r2, g2, b2 uint8) int {
+	dr, dg, db := int(r1)-int(r2), int(g1)-int(g2), int(b1)-int(b2)
+	return dr*dr + dg*dg + db*db
+}
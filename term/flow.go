@@ -0,0 +1,77 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// FlowControl returns whether software flow control (IXON) is enabled
+// for the terminal, i.e. whether ^S stops and ^Q resumes the output.
+// It returns an error if the file descriptor fd is not connected to
+// a terminal.
+func FlowControl(fd uintptr) (bool, error) {
+	termios, err := unix.IoctlGetTermios(int(fd), termiosGet)
+	if err != nil {
+		return false, err
+	}
+	return termios.Iflag&unix.IXON != 0, nil
+}
+
+// SetFlowControl enables or disables software flow control (IXON and IXOFF)
+// without changing other settings of the terminal, so that ^S and ^Q can be
+// read like other keys. It returns an error if the file descriptor fd
+// is not connected to a terminal. The returned function can be used to
+// restore the previous setting.
+//   restore, err := term.SetFlowControl(os.Stdin.Fd(), false)
+//   if err != nil {
+//       panic(err)
+//   }
+//   defer restore()
+func SetFlowControl(fd uintptr, enable bool) (func() error, error) {
+	termios, err := unix.IoctlGetTermios(int(fd), termiosGet)
+	if err != nil {
+		return nil, err
+	}
+	old := termios.Iflag & (unix.IXON | unix.IXOFF)
+	if enable {
+		termios.Iflag |= unix.IXON | unix.IXOFF
+	} else {
+		termios.Iflag &^= unix.IXON | unix.IXOFF
+	}
+	if err = unix.IoctlSetTermios(int(fd), termiosSet, termios); err != nil {
+		return nil, err
+	}
+	return func() error {
+		termios, err := unix.IoctlGetTermios(int(fd), termiosGet)
+		if err != nil {
+			return err
+		}
+		termios.Iflag = termios.Iflag&^(unix.IXON|unix.IXOFF) | old
+		return unix.IoctlSetTermios(int(fd), termiosSet, termios)
+	}, nil
+}
+
+// OutputStopped returns whether the output to the terminal has been stopped
+// with ^S (if flow control is enabled). It writes a NUL character, which is
+// ignored by terminals, and reports true if the write does not complete
+// within the timeout. In this case the write completes after the output
+// has been resumed with ^Q.
+func OutputStopped(fd uintptr, timeout time.Duration) (bool, error) {
+	if on, err := FlowControl(fd); err != nil || !on {
+		return false, err
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, err := unix.Write(int(fd), []byte{0})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		return false, err
+	case <-time.After(timeout):
+		return true, nil
+	}
+}
@@ -0,0 +1,201 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Font is a font for large letters (see Banner). All rows of a glyph
+// must have the same width.
+type Font struct {
+	Height  int               // number of rows of the glyphs
+	Spacing int               // number of columns between glyphs
+	Glyphs  map[rune][]string // the rows of the glyphs
+}
+
+// SmallFont is a font with 5 rows for the upper case letters, the digits
+// and some punctuation; lower case letters are shown as upper case.
+var SmallFont = Font{Height: 5, Spacing: 1, Glyphs: map[rune][]string{
+	'A':  {" ### ", "#   #", "#####", "#   #", "#   #"},
+	'B':  {"#### ", "#   #", "#### ", "#   #", "#### "},
+	'C':  {" ####", "#    ", "#    ", "#    ", " ####"},
+	'D':  {"#### ", "#   #", "#   #", "#   #", "#### "},
+	'E':  {"#####", "#    ", "#### ", "#    ", "#####"},
+	'F':  {"#####", "#    ", "#### ", "#    ", "#    "},
+	'G':  {" ####", "#    ", "#  ##", "#   #", " ####"},
+	'H':  {"#   #", "#   #", "#####", "#   #", "#   #"},
+	'I':  {"###", " # ", " # ", " # ", "###"},
+	'J':  {"  ###", "    #", "    #", "#   #", " ### "},
+	'K':  {"#   #", "#  # ", "###  ", "#  # ", "#   #"},
+	'L':  {"#    ", "#    ", "#    ", "#    ", "#####"},
+	'M':  {"#   #", "## ##", "# # #", "#   #", "#   #"},
+	'N':  {"#   #", "##  #", "# # #", "#  ##", "#   #"},
+	'O':  {" ### ", "#   #", "#   #", "#   #", " ### "},
+	'P':  {"#### ", "#   #", "#### ", "#    ", "#    "},
+	'Q':  {" ### ", "#   #", "# # #", "#  # ", " ## #"},
+	'R':  {"#### ", "#   #", "#### ", "#  # ", "#   #"},
+	'S':  {" ####", "#    ", " ### ", "    #", "#### "},
+	'T':  {"#####", "  #  ", "  #  ", "  #  ", "  #  "},
+	'U':  {"#   #", "#   #", "#   #", "#   #", " ### "},
+	'V':  {"#   #", "#   #", "#   #", " # # ", "  #  "},
+	'W':  {"#   #", "#   #", "# # #", "## ##", "#   #"},
+	'X':  {"#   #", " # # ", "  #  ", " # # ", "#   #"},
+	'Y':  {"#   #", " # # ", "  #  ", "  #  ", "  #  "},
+	'Z':  {"#####", "   # ", "  #  ", " #   ", "#####"},
+	'0':  {" ### ", "#  ##", "# # #", "##  #", " ### "},
+	'1':  {" # ", "## ", " # ", " # ", "###"},
+	'2':  {" ### ", "#   #", "  ## ", " #   ", "#####"},
+	'3':  {"#### ", "    #", " ### ", "    #", "#### "},
+	'4':  {"#   #", "#   #", "#####", "    #", "    #"},
+	'5':  {"#####", "#    ", "#### ", "    #", "#### "},
+	'6':  {" ### ", "#    ", "#### ", "#   #", " ### "},
+	'7':  {"#####", "    #", "   # ", "  #  ", "  #  "},
+	'8':  {" ### ", "#   #", " ### ", "#   #", " ### "},
+	'9':  {" ### ", "#   #", " ####", "    #", " ### "},
+	' ':  {"  ", "  ", "  ", "  ", "  "},
+	'.':  {" ", " ", " ", " ", "#"},
+	',':  {"  ", "  ", "  ", " #", "# "},
+	':':  {" ", "#", " ", "#", " "},
+	'!':  {"#", "#", "#", " ", "#"},
+	'?':  {"### ", "   #", " ## ", "    ", " #  "},
+	'-':  {"   ", "   ", "###", "   ", "   "},
+	'\'': {"#", "#", " ", " ", " "},
+	'/':  {"    #", "   # ", "  #  ", " #   ", "#    "},
+}}
+
+// glyph returns the glyph for ch; if there is none, the glyph for the upper
+// case variant is tried.
+func (f Font) glyph(ch rune) ([]string, bool) {
+	if g, ok := f.Glyphs[ch]; ok {
+		return g, true
+	}
+	g, ok := f.Glyphs[unicode.ToUpper(ch)]
+	return g, ok
+}
+
+// width returns the number of columns of s; characters without a glyph
+// are skipped.
+func (f Font) width(s string) int {
+	w, n := 0, 0
+	for _, ch := range s {
+		if g, ok := f.glyph(ch); ok && len(g) > 0 {
+			w += StringWidth(g[0])
+			n++
+		}
+	}
+	if n > 1 {
+		w += (n - 1) * f.Spacing
+	}
+	return w
+}
+
+// render returns the rows of s in the font; characters without a glyph
+// are skipped.
+func (f Font) render(s string) []string {
+	rows := make([]strings.Builder, f.Height)
+	first := true
+	for _, ch := range s {
+		g, ok := f.glyph(ch)
+		if !ok {
+			continue
+		}
+		for i := range rows {
+			if !first {
+				rows[i].WriteString(strings.Repeat(" ", f.Spacing))
+			}
+			if i < len(g) {
+				rows[i].WriteString(g[i])
+			}
+		}
+		first = false
+	}
+	lines := make([]string, len(rows))
+	for i := range rows {
+		lines[i] = strings.TrimRight(rows[i].String(), " ")
+	}
+	return lines
+}
+
+// ErrFontFormat is returned by ParseFont if the font is invalid.
+var ErrFontFormat = errors.New("invalid font")
+
+// figletChars are the characters of a FIGlet font that are not code tagged.
+var figletChars = []rune("ÄÖÜäöüß")
+
+// ParseFont reads a FIGlet font (.flf file). The glyphs are put side by side
+// without kerning or smushing.
+//   f, _ := os.Open("standard.flf")
+//   font, err := term.ParseFont(f)
+func ParseFont(r io.Reader) (Font, error) {
+	sc := bufio.NewScanner(r)
+	if !sc.Scan() {
+		return Font{}, ErrFontFormat
+	}
+	// flf2a<hardblank> height baseline max_length old_layout comment_lines ...
+	header := strings.Fields(sc.Text())
+	if len(header) < 6 || !strings.HasPrefix(header[0], "flf2a") || len(header[0]) < 6 {
+		return Font{}, ErrFontFormat
+	}
+	hardblank := header[0][5:6]
+	height, err1 := strconv.Atoi(header[1])
+	comments, err2 := strconv.Atoi(header[5])
+	if err1 != nil || err2 != nil || height <= 0 {
+		return Font{}, ErrFontFormat
+	}
+	for i := 0; i < comments; i++ {
+		sc.Scan()
+	}
+	font := Font{Height: height, Glyphs: map[rune][]string{}}
+	readGlyph := func() ([]string, bool) {
+		rows := make([]string, height)
+		for i := range rows {
+			if !sc.Scan() {
+				return nil, false
+			}
+			row := strings.TrimRight(sc.Text(), " ")
+			if row == "" {
+				return nil, false
+			}
+			// the rows end with one or two end marks (the last character)
+			row = strings.TrimRight(row, row[len(row)-1:])
+			rows[i] = strings.ReplaceAll(row, hardblank, " ")
+		}
+		return rows, true
+	}
+	for ch := rune(' '); ch <= '~'; ch++ {
+		g, ok := readGlyph()
+		if !ok {
+			return Font{}, ErrFontFormat
+		}
+		font.Glyphs[ch] = g
+	}
+	for _, ch := range figletChars {
+		g, ok := readGlyph()
+		if !ok {
+			return font, sc.Err()
+		}
+		font.Glyphs[ch] = g
+	}
+	// code tagged characters: a line with the code followed by the glyph
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		code, err := strconv.ParseInt(fields[0], 0, 32)
+		g, ok := readGlyph()
+		if !ok {
+			break
+		}
+		if err == nil && code >= 0 {
+			font.Glyphs[rune(code)] = g
+		}
+	}
+	return font, sc.Err()
+}
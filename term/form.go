@@ -0,0 +1,372 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Field is one input field of a Form.
+// If Value is of type *bool, the function YesNo is used to get the input
+// (a bool Opt.Default selects the default answer), if Choices is not nil,
+// the function MenuWithDefault is used and Value must be of type *uint,
+// otherwise the function Input is used. If Widget is true, Checkbox and
+// RadioGroup are used instead of YesNo and MenuWithDefault (except in
+// non-interactive mode).
+type Field struct {
+	Name    string                                    // must be unique within a form
+	Prompt  string                                    // prompt to show
+	Value   interface{}                               // address of a variable
+	Opt     *InputOpt                                 // optional
+	Choices []string                                  // optional
+	ShowIf  func(answers map[string]interface{}) bool // optional
+	Step    string                                    // optional, title of the step that starts with this field
+	Widget  bool                                      // use Checkbox or RadioGroup
+}
+
+// Form is a sequence of input fields that are filled in one after the other.
+// A field with a ShowIf function is only shown if the function returns true
+// for the answers given so far.
+//   form := &term.Form{Fields: []*term.Field{
+//       {Name: "proxy", Prompt: "Use proxy?", Value: &useProxy},
+//       {Name: "addr", Prompt: "Proxy address: ", Value: &addr,
+//           ShowIf: func(a map[string]interface{}) bool { return a["proxy"] == true }},
+//   }}
+//
+// If Steps is not nil, the fields are grouped into steps: a step starts
+// with a field with a Step title and the indicator is printed before
+// the first shown field of each step.
+//
+// The input of a field can be limited with Opt.Timeout (not for widgets);
+// Opt.OnTimeout selects whether the form is aborted (ErrTimeout is
+// returned), the default value is used or the field is skipped (it has
+// no answer then):
+//   {Name: "region", Prompt: "Region: ", Value: &region, Opt: &term.InputOpt{
+//       Default: "eu", Timeout: 30 * time.Second, OnTimeout: term.TimeoutDefault}},
+//
+// If Confirm is true, a summary of the answers is shown at the end
+// (values of fields that are not echoed are masked) and the user can
+// apply them, cancel the form (ErrCanceled) or change a field by its
+// number; the current value is the default then.
+//
+// The answers can be saved with WriteJSON or WriteYAML, e.g. to generate
+// a config file, and used as Defaults when the form is run again:
+//   if p, err := term.FileAnswers("answers.json"); err == nil {
+//       form.Defaults = p
+//   }
+type Form struct {
+	Fields   []*Field
+	Steps    *StepIndicator // optional
+	Confirm  bool
+	Defaults AnswerProvider // optional, default values by field name
+	answers  map[string]interface{}
+}
+
+// Run fills in the fields of the form. If the input for a field fails,
+// Run stops and returns the error.
+// The name of a field is the key for its answer in non-interactive mode
+// (see function SetAnswers).
+// It panics if stdin and stdout are not connected to a terminal (unless
+// in non-interactive mode) or if the Value of a field is not a pointer.
+func (f *Form) Run() error {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	checkCanInput()
+	f.answers = make(map[string]interface{})
+	if err := f.fill(-1); err != nil {
+		return err
+	}
+	for f.Confirm {
+		edit, err := f.confirm()
+		if err != nil || edit < 0 {
+			return err
+		}
+		if err := f.fill(edit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fill runs the fields that are shown and have no answer yet and
+// the field with the index edit (-1: none). The answers of fields that
+// are not shown are removed.
+func (f *Form) fill(edit int) error {
+	var titles []string
+	for _, fld := range f.Fields {
+		if fld.Step != "" {
+			titles = append(titles, fld.Step)
+		}
+	}
+	step, shown := -1, -1
+	for i, fld := range f.Fields {
+		if fld.Step != "" {
+			step++
+		}
+		if fld.ShowIf != nil && !fld.ShowIf(f.answers) {
+			delete(f.answers, fld.Name)
+			continue
+		}
+		current, answered := f.answers[fld.Name]
+		if answered && i != edit {
+			continue
+		}
+		if !answered && f.Defaults != nil {
+			if s, ok := f.Defaults.Answer(fld.Name); ok {
+				current = fld.parse(s)
+			}
+		}
+		if f.Steps != nil && step >= 0 && step != shown {
+			shown = step
+			writeOut(func() { fmt.Fprintln(out, f.Steps.Format(step, titles)) })
+		}
+		if err := fld.run(current); err == ErrSkipped {
+			delete(f.answers, fld.Name)
+			continue
+		} else if err != nil {
+			return err
+		}
+		f.answers[fld.Name] = reflect.Indirect(reflect.ValueOf(fld.Value)).Interface()
+	}
+	return nil
+}
+
+// confirm shows the summary of the answers and asks for confirmation.
+// It returns the index of the field that should be changed or -1 if
+// the answers are applied.
+func (f *Form) confirm() (int, error) {
+	var labels, values []string
+	var indexes []int
+	width := 0
+	for i, fld := range f.Fields {
+		v, ok := f.answers[fld.Name]
+		if !ok {
+			continue
+		}
+		label := promptKey(fld.Prompt)
+		width = maxInt(width, StringWidth(label))
+		labels = append(labels, label)
+		values = append(values, fld.format(v))
+		indexes = append(indexes, i)
+	}
+	numWidth := len(strconv.Itoa(len(labels)))
+	writeOut(func() {
+		for i, label := range labels {
+			fmt.Fprintf(out, "%*d) %s%s  %s\n", numWidth, i+1, label,
+				strings.Repeat(" ", width-StringWidth(label)), values[i])
+		}
+	})
+	yesNo := []rune(strings.ToLower(msg(MsgYesNo)))
+	opt := &InputOpt{Key: "confirm", Default: -1}
+	opt.ConvFunc = func(s string) (interface{}, error) {
+		if r := []rune(strings.ToLower(s)); len(r) == 1 && len(yesNo) == 2 {
+			switch r[0] {
+			case yesNo[0]:
+				return -1, nil
+			case yesNo[1]:
+				return -2, nil
+			}
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return 0, err
+		}
+		if n < 1 || n > len(labels) {
+			return 0, errNoOption
+		}
+		return indexes[n-1], nil
+	}
+	var idx int
+	if err := inputAny(fmt.Sprintf(msg(MsgConfirm), string(yesNo)), &idx, opt); err != nil {
+		return 0, err
+	}
+	if idx == -2 {
+		return 0, ErrCanceled
+	}
+	return idx, nil
+}
+
+// parse converts a default value to the type of the field; it returns
+// nil if this is not possible.
+func (fld *Field) parse(s string) interface{} {
+	if fld.Choices != nil {
+		for i, c := range fld.Choices {
+			if c == s {
+				return uint(i)
+			}
+		}
+		return nil
+	}
+	switch fld.Value.(type) {
+	case *bool:
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+		return nil
+	case *string:
+		return s
+	}
+	opt := &InputOpt{}
+	if fld.Opt != nil {
+		opt.ConvFunc = fld.Opt.ConvFunc
+	}
+	v := reflect.New(reflect.TypeOf(fld.Value).Elem())
+	if err := converter(v.Interface(), opt)(s); err != nil {
+		return nil
+	}
+	return v.Elem().Interface()
+}
+
+// exportValue returns the answer v for WriteJSON and WriteYAML.
+func (fld *Field) exportValue(v interface{}) interface{} {
+	if i, ok := v.(uint); ok && fld.Choices != nil && i < uint(len(fld.Choices)) {
+		return fld.Choices[i]
+	}
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v
+	}
+	return fmt.Sprint(v)
+}
+
+// exported returns the fields with answers that are exported.
+func (f *Form) exported() []*Field {
+	var fields []*Field
+	for _, fld := range f.Fields {
+		if _, ok := f.answers[fld.Name]; ok && (fld.Opt == nil || fld.Opt.Echo == EchoNormal) {
+			fields = append(fields, fld)
+		}
+	}
+	return fields
+}
+
+// WriteJSON writes the answers of the last call of Run as a JSON object
+// to w. Choices are written as text. The answers of fields that are not
+// echoed (e.g. passwords) are omitted.
+func (f *Form) WriteJSON(w io.Writer) error {
+	m := map[string]interface{}{}
+	for _, fld := range f.exported() {
+		m[fld.Name] = fld.exportValue(f.answers[fld.Name])
+	}
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+// WriteYAML writes the answers of the last call of Run like WriteJSON,
+// but as "name: value" lines (the subset of YAML that is read by
+// FileAnswers).
+func (f *Form) WriteYAML(w io.Writer) error {
+	var b strings.Builder
+	for _, fld := range f.exported() {
+		v := fld.exportValue(f.answers[fld.Name])
+		if s, ok := v.(string); ok {
+			v = strconv.Quote(s)
+		}
+		fmt.Fprintf(&b, "%s: %v\n", fld.Name, v)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// format returns the answer v for the summary of a form.
+func (fld *Field) format(v interface{}) string {
+	if fld.Opt != nil && fld.Opt.Echo != EchoNormal {
+		return strings.Repeat(string(maskChar), 6)
+	}
+	if i, ok := v.(uint); ok && fld.Choices != nil && i < uint(len(fld.Choices)) {
+		return fld.Choices[i]
+	}
+	return fmt.Sprint(v)
+}
+
+// Answers returns the values of all fields that were shown in the last
+// call of Run, keyed by the field names.
+func (f *Form) Answers() map[string]interface{} {
+	answers := make(map[string]interface{}, len(f.answers))
+	for k, v := range f.answers {
+		answers[k] = v
+	}
+	return answers
+}
+
+// run gets the input for the field; if current is not nil, it is
+// the default value.
+func (fld *Field) run(current interface{}) error {
+	if val := reflect.ValueOf(fld.Value); val.Kind() != reflect.Ptr {
+		panic(fmt.Sprintf("value of field %q not a pointer: %s", fld.Name, val.Type()))
+	}
+	opt := &InputOpt{}
+	if fld.Opt != nil {
+		*opt = *fld.Opt
+	}
+	if current != nil {
+		opt.Default = current
+	}
+	if opt.Key == "" {
+		opt.Key = fld.Name
+	}
+	widget := fld.Widget && !isNonInteractive()
+	switch v := fld.Value.(type) {
+	case *bool:
+		if widget {
+			dflt, _ := opt.Default.(bool)
+			checked, err := checkbox(fld.Prompt, dflt)
+			if err == nil {
+				*v = checked
+			}
+			return err
+		}
+		options := []rune(strings.ToLower(msg(MsgYesNo)))
+		if dflt, ok := opt.Default.(bool); ok && len(options) == 2 {
+			if dflt {
+				options[0] = unicode.ToUpper(options[0])
+			} else {
+				options[1] = unicode.ToUpper(options[1])
+			}
+		}
+		yes, err := yesNo(fld.Prompt, string(options), opt)
+		if err != nil {
+			return err
+		}
+		*v = yes
+	case *uint:
+		if fld.Choices == nil {
+			return inputAny(fld.Prompt, fld.Value, opt)
+		}
+		if dflt, ok := opt.Default.(uint); !ok || dflt >= uint(len(fld.Choices)) {
+			opt.Default = nil
+		}
+		if widget {
+			dflt, _ := opt.Default.(uint)
+			writeOut(func() { fmt.Fprintln(out, fld.Prompt) })
+			idx, err := radioGroup(fld.Choices, dflt)
+			if err == nil {
+				*v = idx
+			}
+			return err
+		}
+		idx, err := menu(fld.Prompt, "", fld.Choices, 0, opt)
+		if err != nil {
+			return err
+		}
+		*v = idx
+	default:
+		if fld.Choices != nil {
+			panic(fmt.Sprintf("value of field %q with choices not *uint", fld.Name))
+		}
+		return inputAny(fld.Prompt, fld.Value, opt)
+	}
+	return nil
+}
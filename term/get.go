@@ -3,19 +3,48 @@
 package term
 
 import (
-	"io"
+	"bytes"
+	"errors"
+	"fmt"
 	"os"
-	"strconv"
+	"os/signal"
+	"strings"
+	"time"
 	"unicode"
 	"unicode/utf8"
 
 	"golang.org/x/sys/unix"
 )
 
+// ErrInterrupted is returned if the input was interrupted with ^C
+// and the policy is InterruptError.
+var ErrInterrupted = errors.New("interrupted")
+
+// ErrCanceled is returned if the input was canceled with Esc
+// (see function SetCancelOnEsc).
+var ErrCanceled = errors.New("canceled")
+
+// ErrEOF is returned if ^D (the EOF character of the terminal) was typed
+// while the input was empty. If the terminal was closed, io.EOF is
+// returned instead.
+var ErrEOF = errors.New("EOF typed")
+
+// ErrTimeout is returned if the input was not finished within
+// InputOpt.Timeout.
+var ErrTimeout = errors.New("input timeout")
+
+// ErrSkipped is returned if InputOpt.Timeout expired and the policy is
+// TimeoutSkip; a Form continues with the next field then.
+var ErrSkipped = errors.New("input skipped")
+
+// ErrControlChar is returned if a control character was typed or pasted
+// and the policy is ControlReject (and by Keychain.Set).
+var ErrControlChar = errors.New("control character in input")
+
 const (
 	maskChar = '*'
 	linefeed = '\n'
-	space    = ' '
+	bell     = "\a"
 )
 
 type EchoMode uint8
@@ -27,115 +56,735 @@ const (
 )
 
 // GetBytes gets input from a terminal and returns it as a slice of bytes,
-// which does not include the final \n (if any).
+// which does not include the final \n (if any). The input also ends with
+// the EOL and EOL2 characters of the terminal (see stty(1)) and with CR
+// if the terminal does not translate it to \n; these characters are not
+// included either.
 // The echo parameter controls what is printed to the screen.
 // If limit > 0, its the max. number of characters to get; if the number is
 // reached the input will be submitted w/o typing enter.
+// Typing ^C is handled according to the policy set with SetInterruptPolicy.
+// If the process is suspended (e.g. with ^Z), the terminal state is restored
+// and the echoed input is printed again when the process is continued.
+// It is printed again at the new width, too, if the window is resized.
 // It panics if stdin and stdout are not connected to a terminal.
 func GetBytes(echo EchoMode, limit uint8) ([]byte, error) {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	b, _, err := getBytes("", &InputOpt{Echo: echo, Limit: uint(limit)})
+	return b, err
+}
+
+// Terminator tells how an input was ended.
+type Terminator uint8
+
+const (
+	EndEnter      Terminator = iota // Enter was typed
+	EndEOF                          // ^D was typed
+	EndLimit                        // the limit was reached
+	EndInterrupt                    // ^C was typed (policy InterruptError)
+	EndError                        // reading from the terminal failed
+	EndEscape                       // Esc was typed (see SetCancelOnEsc)
+	EndTimeout                      // InputOpt.Timeout expired
+	EndTerminator                   // a character in InputOpt.Terminators was typed
+)
+
+// GetBytesTerm does the same as GetBytes but takes the options from opt
+// (ConvFunc, Default and Key are not used) and also returns how the input
+// was ended.
+// It panics if stdin and stdout are not connected to a terminal.
+func GetBytesTerm(opt *InputOpt) ([]byte, Terminator, error) {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	if opt == nil {
+		opt = &InputOpt{}
+	}
+	return getBytes("", opt)
+}
+
+func getBytes(prompt string, opt *InputOpt) ([]byte, Terminator, error) {
+	return editBytes(prompt, nil, opt)
+}
+
+// editBytes is like getBytes; the input starts with the content of init.
+func editBytes(prompt string, init []byte, opt *InputOpt) ([]byte, Terminator, error) {
 	checkIsTerminal()
-	result := []byte{}
-	stdoutFd := int(os.Stdout.Fd())
-	termios, err := unix.IoctlGetTermios(stdoutFd, termiosGet)
+	r := &reader{prompt: prompt, opt: opt, fd: int(inFile.Fd())}
+	r.LineBuffer = LineBuffer{w: out, mode: opt.Echo, buf: append([]byte{}, init...), pos: len(init)}
+	defer r.useTypeahead()()
+	if opt.Timeout > 0 {
+		r.deadline = time.Now().Add(opt.Timeout)
+	}
+	if h := r.history(); h != nil {
+		r.histIndex = len(h.lines)
+	}
+	termios, err := unix.IoctlGetTermios(r.fd, termiosGet)
 	if err != nil {
-		return result, err
+		return r.buf, EndError, err
+	}
+	r.old = *termios
+	r.raw = *termios
+	r.raw.Cc[unix.VMIN] = 1
+	r.raw.Cc[unix.VTIME] = 0
+	// ISIG is disabled, so that the terminal state can be restored
+	// before a signal is sent.
+	r.raw.Lflag &^= unix.ECHO | unix.ICANON | unix.ISIG
+	if opt.ISIG == TermOn {
+		r.raw.Lflag |= unix.ISIG
+	}
+	switch {
+	case opt.IXON == TermOn:
+		r.raw.Iflag |= unix.IXON
+	case opt.IXON == TermOff, keyMap["\x13"] != EditNone || keyMap["\x11"] != EditNone:
+		// ^S and ^Q can only be read if flow control is disabled
+		r.raw.Iflag &^= unix.IXON | unix.IXOFF
+	}
+	switch opt.IEXTEN {
+	case TermOn:
+		r.raw.Lflag |= unix.IEXTEN
+	case TermOff:
+		r.raw.Lflag &^= unix.IEXTEN
 	}
-	old := *termios
-	defer unix.IoctlSetTermios(stdoutFd, termiosSet, &old)
 
-	termios.Cc[unix.VMIN] = 1
-	termios.Cc[unix.VTIME] = 0
-	termios.Lflag &^= unix.ECHO | unix.ICANON
-	termios.Iflag |= unix.ICRNL
-	unix.IoctlSetTermios(stdoutFd, termiosSet, termios)
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, unix.SIGTSTP, unix.SIGCONT, unix.SIGWINCH)
+	done := make(chan struct{})
+	defer func() {
+		outMu.Lock()
+		r.finished = true
+		activeReader = nil
+		inputRows = r.rows(len(r.buf))
+		r.restore()
+		if r.promptOut != nil {
+			if r.echoNewline {
+				out.WriteString("\r\n")
+			}
+			setOutFile(r.promptOut)
+		}
+		outMu.Unlock()
+		signal.Stop(sigCh)
+		close(done)
+	}()
+	go r.handleSignals(sigCh, done)
 
-	vEof := termios.Cc[unix.VEOF]
-	vErase := termios.Cc[unix.VERASE]
-	vKill := termios.Cc[unix.VKILL]
-	vWerase := termios.Cc[unix.VWERASE]
+	outMu.Lock()
+	if f := echoOutput(); f != outFile {
+		r.echoTo(f)
+	}
+	activeReader = r
+	r.setRaw()
+	out.WriteString(r.displayPrompt())
+	r.setTabStops()
+	if r.highlight() != nil {
+		r.renderHighlighted()
+	} else {
+		r.echoRange(0, len(r.buf))
+	}
+	r.showTail()
+	out.Flush()
+	outMu.Unlock()
 
-	var cnt int
-loop:
 	for {
-		buf := []byte{0, 0, 0, 0}
-		cnt, err = os.Stdin.Read(buf)
-		if err != nil {
-			return result, err
-		}
-		switch buf[0] {
-		case vEof:
-			if len(result) == 0 {
-				err = io.EOF
-			}
-			break loop
-		case linefeed:
-			break loop
-		case vErase:
-			if len(result) > 0 {
-				_, n := utf8.DecodeLastRune(result)
-				result = erase(n, result, echo != EchoNone)
+		key, err := r.readKey()
+		switch {
+		case err == ErrTimeout:
+			return r.buf, EndTimeout, err
+		case err != nil:
+			return r.buf, EndError, err
+		}
+		outMu.Lock()
+		if r.search == nil {
+			r.cursorRow = r.rows(r.pos) - 1
+		}
+		fin, err := r.handle(key)
+		if (r.bidi() != nil || r.highlight() != nil) && r.search == nil {
+			// the order or the colors of all characters may have changed
+			r.render()
+		}
+		if fin {
+			r.moveTo(len(r.buf))
+			if r.tailShown {
+				out.WriteString("\x1b[K")
 			}
-		case vKill:
-			result = erase(len(result), result, echo != EchoNone)
-		case vWerase:
-			if len(result) == 0 {
-				break
+		} else {
+			r.showTail()
+		}
+		out.Flush()
+		outMu.Unlock()
+		if fin {
+			if h := r.history(); h != nil && r.end == EndEnter {
+				h.Add(string(r.buf))
 			}
-			flag := false
-			var pos int
-			for pos = len(result) - 1; pos >= 0; pos-- {
-				if !flag && result[pos] != space {
-					flag = true
-					continue
-				}
-				if flag && result[pos] == space {
-					break
+			return r.buf, r.end, err
+		}
+	}
+}
+
+// rows returns the number of rows on the screen the prompt and the input
+// up to pos occupy; the cursor is in the last row if it is at pos.
+func (r *reader) rows(pos int) int {
+	cols, _ := getTermSize()
+	lines := strings.Split(r.displayPrompt(), "\n")
+	var n int
+	for _, l := range lines[:len(lines)-1] {
+		n += screenRows(textWidth(l), cols)
+	}
+	return n + screenRows(r.promptCol+textWidth(lines[len(lines)-1])+r.span(0, pos), cols)
+}
+
+// inputRows is the number of rows of the last input (see resetPrompt);
+// outMu must be locked when it is used.
+var inputRows = 1
+
+// activeReader is the reader of the running input function (if any);
+// outMu must be locked when it is used.
+var activeReader *reader
+
+type reader struct {
+	keyReader
+	LineBuffer
+	prompt   string
+	opt      *InputOpt
+	fd       int
+	old, raw unix.Termios
+	finished bool
+	end      Terminator
+	viNormal bool // vi normal mode
+	viOp     byte // pending vi operator
+	// index of the history line that is edited and the saved new line
+	histIndex int
+	histSaved []byte
+	search    *historySearch
+	bindings  *bindingsView // shown by showBindings
+	tailShown bool          // placeholder or counter shown after the input
+	// the row of the cursor relative to the first row of the prompt
+	// (the input may be wrapped)
+	cursorRow int
+	// the prompt output if the input is echoed to another file (see
+	// SetEchoOutput), the column after the prompt and whether the
+	// echoed input ends with a newline
+	promptOut   *os.File
+	promptCol   int
+	echoNewline bool
+}
+
+// echoTo writes the prompt to the prompt output and switches the output
+// to the file f; outMu must be locked.
+func (r *reader) echoTo(f *os.File) {
+	out.WriteString(r.prompt)
+	r.promptOut = outFile
+	if IsTerminal(outFile.Fd()) {
+		cols, _ := getTermSize()
+		lines := strings.Split(r.prompt, "\n")
+		r.promptCol = textWidth(lines[len(lines)-1]) % cols
+	} else {
+		r.echoNewline = true
+	}
+	r.prompt = ""
+	setOutFile(f)
+}
+
+// lineStart returns the sequence that moves the cursor to the start of
+// the row of the prompt or after the prompt if it was written to another
+// file.
+// ANSI escape codes: Cursor Horizontal Absolute (CHA: ESC[nG).
+func (r *reader) lineStart() string {
+	if r.promptCol > 0 {
+		return fmt.Sprintf("\x1b[%dG", r.promptCol+1)
+	}
+	return "\r"
+}
+
+func (r *reader) setRaw() {
+	unix.IoctlSetTermios(r.fd, termiosSet, &r.raw)
+	// the line editor only understands the traditional key encoding
+	out.WriteString(keyboardOffSeq() + bracketedPasteOn + r.viCursorSeq())
+}
+
+func (r *reader) restore() {
+	out.WriteString(bracketedPasteOff + keyboardOnSeq())
+	if r.viCursorSeq() != "" {
+		out.WriteString(cursorStyleSeq(CursorDefault, false))
+	}
+	out.Flush()
+	unix.IoctlSetTermios(r.fd, termiosSet, &r.old)
+}
+
+// render prints the prompt and the echoed input again.
+func (r *reader) render() {
+	if syncUpdates {
+		out.WriteString(syncUpdateBegin)
+		defer out.WriteString(syncUpdateEnd)
+	}
+	cursorUp(out, r.cursorRow)
+	out.WriteString(r.lineStart() + "\x1b[J")
+	if r.search != nil {
+		r.renderSearch()
+		return
+	}
+	r.cursorRow = r.rows(r.pos) - 1
+	out.WriteString(r.displayPrompt())
+	r.setTabStops()
+	if f := r.bidi(); f != nil {
+		s, col := r.bidiLayout(f)
+		out.WriteString(s)
+		cursorBack(out, StringWidth(s)-col)
+	} else if r.highlight() != nil {
+		r.renderHighlighted()
+	} else {
+		r.Render()
+	}
+	r.tailShown = false
+	r.showTail()
+}
+
+// highlight returns InputOpt.Highlight or nil if the input is not
+// echoed normally.
+func (r *reader) highlight() func(string) []StyledSegment {
+	if r.opt.Echo != EchoNormal {
+		return nil
+	}
+	return r.opt.Highlight
+}
+
+// renderHighlighted prints the input with the styles returned by
+// InputOpt.Highlight and moves the cursor to its position. The input is
+// printed without styles if the texts of the segments do not add up to it.
+// ANSI escape codes: Erase in Line (EL: ESC[K).
+func (r *reader) renderHighlighted() {
+	var text, styled strings.Builder
+	for _, seg := range r.opt.Highlight(string(r.buf)) {
+		text.WriteString(seg.Text)
+		styled.WriteString(seg.Style.Render(seg.Text))
+	}
+	if text.String() != string(r.buf) {
+		r.Render()
+		return
+	}
+	out.WriteString(styled.String() + "\x1b[K")
+	cursorBack(out, r.span(r.pos, len(r.buf)))
+}
+
+// showTail prints the placeholder (if the input is empty) and the counter
+// for InputOpt.MaxLen after the input and moves the cursor back.
+// ANSI escape codes: Erase in Line (EL: ESC[K).
+func (r *reader) showTail() {
+	var s string
+	var width int
+	if r.search == nil {
+		if len(r.buf) == 0 && r.opt.Placeholder != "" {
+			s = theme.Muted.Render(r.opt.Placeholder)
+			width = StringWidth(r.opt.Placeholder)
+		}
+		if r.opt.ShowCount && r.opt.MaxLen > 0 {
+			count := fmt.Sprintf(" %d/%d", len(r.buf), r.opt.MaxLen)
+			s += count
+			width += len(count)
+		}
+	}
+	if s == "" && !r.tailShown {
+		return
+	}
+	tail := r.tailWidth()
+	cursorForward(out, tail)
+	out.WriteString("\x1b[K" + s)
+	cursorBack(out, width+tail)
+	r.tailShown = s != ""
+}
+
+// handleSignals restores the terminal state before the process is stopped
+// and sets it again and re-renders the input when the process is continued.
+func (r *reader) handleSignals(sigCh chan os.Signal, done chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case sig := <-sigCh:
+			outMu.Lock()
+			if !r.finished {
+				switch sig {
+				case unix.SIGTSTP:
+					if r.bindings != nil {
+						out.WriteString(altScreenOff)
+					}
+					r.restore()
+					signal.Reset(unix.SIGTSTP)
+					unix.Kill(os.Getpid(), unix.SIGTSTP)
+					signal.Notify(sigCh, unix.SIGTSTP)
+				case unix.SIGWINCH:
+					// most terminals rewrap the lines to the new width
+					Size(true)
+					if r.bindings != nil {
+						r.drawBindings()
+						out.Flush()
+						break
+					}
+					if r.search == nil {
+						r.cursorRow = r.rows(r.pos) - 1
+					}
+					r.render()
+					out.Flush()
+				default:
+					r.setRaw()
+					r.cursorRow = 0
+					r.render()
+					if r.bindings != nil {
+						out.WriteString(altScreenOn)
+						r.drawBindings()
+					}
+					out.Flush()
 				}
 			}
-			result = erase(len(result)-(pos+1), result, echo != EchoNone)
-		default:
-			if r, _ := utf8.DecodeRune(buf); unicode.IsGraphic(r) {
-				if echo == EchoNormal {
-					os.Stdout.Write(buf[:cnt])
-				} else if echo == EchoMask {
-					os.Stdout.Write([]byte{maskChar})
-				}
-				result = append(result, buf[:cnt]...)
-				if limit > 0 && utf8.RuneCount(result) == int(limit) {
-					break loop
-				}
+			outMu.Unlock()
+		}
+	}
+}
+
+func (r *reader) raise(sig unix.Signal) {
+	r.restore()
+	unix.Kill(os.Getpid(), sig)
+	r.setRaw()
+}
+
+// handle processes a key read from the terminal. It returns true
+// if the input is finished; r.end is set accordingly.
+// It must be called with outMu locked.
+func (r *reader) handle(key []byte) (bool, error) {
+	cc := &r.raw.Cc
+	if typ, ok := focusEvent(key); ok {
+		if focusFunc != nil {
+			focusFunc(typ == FocusGained)
+		}
+		return false, nil
+	}
+	if r.search != nil && r.handleSearch(key) {
+		return false, nil
+	}
+	if bytes.HasPrefix(key, []byte(pasteStart)) {
+		return r.insert(key[len(pasteStart) : len(key)-len(pasteEnd)])
+	}
+	if end, ok := r.terminator(key); ok {
+		r.end = end
+		return true, nil
+	}
+	if string(key) == "?" && r.opt.menu {
+		r.showBindings()
+		return false, nil
+	}
+	if string(key) == "\t" && r.opt.Tab == TabComplete && r.opt.Complete != nil {
+		r.complete()
+		return false, nil
+	}
+	if r.vi() {
+		if handled, fin, err := r.handleVi(key); handled {
+			return fin, err
+		}
+	}
+	if action, ok := keyMap[string(key)]; ok {
+		return r.edit(action)
+	}
+	if len(key) > 1 && key[0] == escape {
+		// escape sequences are ignored
+		return false, nil
+	}
+	switch key[0] {
+	case escape:
+		if cancelOnEsc {
+			r.end = EndEscape
+			return true, ErrCanceled
+		}
+	case cc[unix.VEOF]:
+		r.end = EndEOF
+		if len(r.buf) == 0 {
+			return true, ErrEOF
+		}
+		return true, nil
+	case cc[unix.VINTR]:
+		policy := r.interruptPolicy()
+		if policy == InterruptKey {
+			return r.insert(key)
+		}
+		if policy == InterruptIgnore {
+			break
+		}
+		if r.opt.OnInterrupt != nil {
+			r.restore()
+			outMu.Unlock()
+			r.opt.OnInterrupt()
+			outMu.Lock()
+			r.setRaw()
+		}
+		if policy == InterruptError {
+			r.end = EndInterrupt
+			return true, ErrInterrupted
+		}
+		r.raise(unix.SIGINT)
+	case cc[unix.VQUIT]:
+		r.raise(unix.SIGQUIT)
+	case cc[unix.VSUSP]:
+		// the signal is handled by handleSignals
+		unix.Kill(os.Getpid(), unix.SIGTSTP)
+	case cc[unix.VERASE]:
+		return r.edit(EditBackwardDeleteChar)
+	case cc[unix.VKILL]:
+		return r.edit(EditUnixLineDiscard)
+	case cc[unix.VWERASE]:
+		return r.edit(EditUnixWordRubout)
+	default:
+		return r.insert(key)
+	}
+	return false, nil
+}
+
+// terminator returns how the input ends if key is a line terminator:
+// newline, CR if the terminal does not translate it (ICRNL is off),
+// the EOL and EOL2 characters of the terminal and InputOpt.Terminators.
+func (r *reader) terminator(key []byte) (Terminator, bool) {
+	if len(key) == 1 {
+		c := key[0]
+		if c == linefeed || c == '\r' && r.raw.Iflag&(unix.ICRNL|unix.IGNCR) == 0 {
+			return EndEnter, true
+		}
+		for _, i := range eolChars {
+			// 0 and 0xFF disable a special character
+			if cc := r.raw.Cc[i]; c == cc && cc != 0 && cc != 0xFF {
+				return EndEnter, true
 			}
 		}
 	}
-	return result, err
+	if ch, n := utf8.DecodeRune(key); n == len(key) && strings.ContainsRune(r.opt.Terminators, ch) {
+		return EndTerminator, true
+	}
+	return EndEnter, false
 }
 
-func erase(n int, result []byte, echo bool) []byte {
-	if echo {
-		if x := utf8.RuneCount(result[len(result)-n:]); x > 0 {
-			os.Stdout.Write([]byte{0x1B, '['})
-			os.Stdout.Write([]byte(strconv.Itoa(x)))
-			os.Stdout.Write([]byte{'D', 0x1B, '[', 'K'})
+// wordStart returns the index in b where the last word starts.
+// Separators after the last word are skipped.
+func wordStart(b []byte, isSep func(rune) bool) int {
+	pos := len(b)
+	inWord := false
+	for pos > 0 {
+		ch, n := utf8.DecodeLastRune(b[:pos])
+		if isSep(ch) {
+			if inWord {
+				break
+			}
+		} else {
+			inWord = true
+		}
+		pos -= n
+	}
+	return pos
+}
+
+// wordEnd returns the index in b where the first word ends.
+// Separators before the first word are skipped.
+func wordEnd(b []byte, isSep func(rune) bool) int {
+	pos := 0
+	inWord := false
+	for pos < len(b) {
+		ch, n := utf8.DecodeRune(b[pos:])
+		if isSep(ch) {
+			if inWord {
+				break
+			}
+		} else {
+			inWord = true
 		}
+		pos += n
 	}
-	return result[:len(result)-n]
+	return pos
 }
 
+// insert inserts the runes in b into the input; control characters are handled
+// according to r.opt.Control. It returns true if the input is finished.
+func (r *reader) insert(b []byte) (bool, error) {
+	for len(b) > 0 {
+		ch, n := utf8.DecodeRune(b)
+		key := b[:n]
+		b = b[n:]
+		allowed := strings.ContainsRune(r.opt.AllowedControls, ch) ||
+			ch == rune(r.raw.Cc[unix.VINTR]) && r.interruptPolicy() == InterruptKey
+		if ch == '\t' {
+			switch r.opt.Tab {
+			case TabInsert:
+				allowed = true
+			case TabSpaces:
+				key = bytes.Repeat([]byte{' '}, r.tabAdvance(r.column(r.pos)))
+				ch, n = ' ', len(key)
+			}
+		}
+		if unicode.IsControl(ch) && !allowed {
+			if r.opt.Control == ControlReject {
+				r.end = EndError
+				return true, ErrControlChar
+			}
+			continue
+		}
+		if !unicode.IsGraphic(ch) && !unicode.IsControl(ch) && ch != zwj && ch != zwnj {
+			continue
+		}
+		if max := r.opt.MaxLen; max > 0 && uint(len(r.buf)+n) > max {
+			out.WriteString(bell)
+			continue
+		}
+		limit := r.opt.Limit
+		if limit > 0 && r.opt.NoAutoSubmit && uint(utf8.RuneCount(r.buf)) >= limit {
+			out.WriteString(bell)
+			continue
+		}
+		if r.tailShown && len(r.buf) == 0 {
+			// erase the placeholder
+			out.WriteString("\x1b[K")
+		}
+		r.Insert(key)
+		if limit > 0 && !r.opt.NoAutoSubmit && uint(utf8.RuneCount(r.buf)) == limit {
+			r.end = EndLimit
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ControlPolicy controls how control characters, that are not used for
+// editing the input, are handled if they are typed or pasted (pasted text
+// can be distinguished from typed text if the terminal supports bracketed paste).
+// Characters in InputOpt.AllowedControls are always inserted.
+type ControlPolicy uint8
+
+const (
+	ControlStrip  ControlPolicy = iota // control characters are dropped (default)
+	ControlReject                      // the input function returns ErrControlChar
+)
+
+// TabPolicy controls how tabs are handled if they are typed or pasted.
+// Tabs in the input are echoed as spaces up to the next tab stop
+// (see InputOpt.TabWidth).
+type TabPolicy uint8
+
+const (
+	TabStrip    TabPolicy = iota // tabs are handled like other control characters (default)
+	TabInsert                    // tabs are inserted
+	TabSpaces                    // tabs are replaced by spaces up to the next tab stop
+	TabComplete                  // a typed tab calls InputOpt.Complete (pasted tabs are dropped)
+)
+
+// complete replaces the input before the cursor with the result of
+// InputOpt.Complete; if there is no completion or the result is too
+// long, the bell is rung.
+func (r *reader) complete() {
+	head := string(r.buf[:r.pos])
+	s := r.opt.Complete(head)
+	if max := r.opt.MaxLen; s == head || max > 0 && uint(len(r.buf)-len(head)+len(s)) > max {
+		out.WriteString(bell)
+		return
+	}
+	r.remove(0, r.pos)
+	r.Insert([]byte(s))
+}
+
+// setTabStops sets the tab stops of the input after the prompt.
+func (r *reader) setTabStops() {
+	lines := strings.Split(r.displayPrompt(), "\n")
+	r.SetTabStops(r.promptCol+textWidth(lines[len(lines)-1]), int(r.opt.TabWidth))
+}
+
+// TimeoutPolicy controls what happens if InputOpt.Timeout expires.
+type TimeoutPolicy uint8
+
+const (
+	TimeoutError   TimeoutPolicy = iota // the input function returns ErrTimeout (a Form is aborted)
+	TimeoutDefault                      // the default value is used (ErrTimeout if there is none)
+	TimeoutSkip                         // the variable is not changed and ErrSkipped is returned
+)
+
+var cancelOnEsc bool
+
+// SetCancelOnEsc sets whether typing Esc cancels all input functions,
+// which then return ErrCanceled. Otherwise Esc is ignored (default).
+func SetCancelOnEsc(b bool) {
+	cancelOnEsc = b
+}
+
+// InterruptPolicy controls what happens if ^C (the INTR character of
+// the terminal) is typed during input. In any case the terminal state
+// is restored before the input function returns or a signal is sent.
+type InterruptPolicy uint8
+
+const (
+	InterruptDefault InterruptPolicy = iota // policy set with SetInterruptPolicy
+	InterruptSignal                         // SIGINT is sent to the process (initial package policy)
+	InterruptError                          // the input function returns ErrInterrupted
+	InterruptIgnore                         // ^C is ignored
+	InterruptKey                            // ^C is inserted into the input like a character
+)
+
+var interruptPolicy = InterruptSignal
+
+// SetInterruptPolicy sets the policy for all input functions, which
+// is used if InputOpt.Interrupt is InterruptDefault.
+// Setting InterruptDefault resets it to InterruptSignal.
+func SetInterruptPolicy(policy InterruptPolicy) {
+	if policy == InterruptDefault {
+		policy = InterruptSignal
+	}
+	interruptPolicy = policy
+}
+
+// interruptPolicy returns the policy for the reader.
+func (r *reader) interruptPolicy() InterruptPolicy {
+	if r.opt.Interrupt == InterruptDefault {
+		return interruptPolicy
+	}
+	return r.opt.Interrupt
+}
+
+// TermMode controls whether a mode of the terminal is enabled while
+// an input function is running (see InputOpt).
+type TermMode uint8
+
+const (
+	TermDefault TermMode = iota // the default of the input functions
+	TermOn                      // the mode is enabled
+	TermOff                     // the mode is disabled
+)
+
 // GetLine gets one line of input from a terminal.
 // It panics if stdin and stdout are not connected to a terminal.
 func GetLine() (string, error) {
-	b, err := GetBytes(EchoNormal, 0)
-	os.Stdout.Write([]byte{linefeed})
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	b, _, err := getBytes("", &InputOpt{})
+	writeOut(func() { out.WriteByte(linefeed) })
 	return string(b), err
 }
 
 // GetPassword gets one line of input from a terminal
-// with the input masked with an * character.
+// with the input masked with an * character. If a credential store is set
+// (see SetCredentialStore), the password is taken from it if possible.
+// The typed password is returned even if saving it in the store fails.
+// The masked input can be replaced after Enter was typed (see
+// SetPasswordRedaction).
 // It panics if stdin and stdout are not connected to a terminal.
 func GetPassword() ([]byte, error) {
-	b, err := GetBytes(EchoMask, 0)
-	os.Stdout.Write([]byte{linefeed})
-	return b, err
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	if b, ok := storedPassword(); ok {
+		return b, nil
+	}
+	b, _, err := getBytes("", &InputOpt{Echo: EchoMask})
+	writeOut(func() {
+		if err == nil && passwordRedaction != RedactNone {
+			// the cursor is at the end of the input
+			cursorBack(out, StringWidth(echoed(string(b), EchoMask)))
+			out.WriteString("\x1b[K" + passwordRedaction.redact(""))
+		}
+		out.WriteByte(linefeed)
+	})
+	if err != nil {
+		return b, err
+	}
+	return b, savePassword(b)
 }
 
 // GetChar gets one character from a terminal.
@@ -147,7 +796,9 @@ func GetChar(echo bool) (rune, error) {
 	} else {
 		mode = EchoNone
 	}
-	b, err := GetBytes(mode, 1)
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	b, _, err := getBytes("", &InputOpt{Echo: mode, Limit: 1})
 	if err != nil {
 		return 0, err
 	}
@@ -0,0 +1,15 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd zos
+
+package term
+
+import "golang.org/x/sys/unix"
+
+// getSid returns the session ID of the foreground process group of
+// the terminal.
+func getSid(fd uintptr) (int, error) {
+	pgrp, err := GetPgrp(fd)
+	if err != nil {
+		return 0, err
+	}
+	return unix.Getsid(pgrp)
+}
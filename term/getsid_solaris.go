@@ -0,0 +1,11 @@
+// +build solaris
+
+package term
+
+import "golang.org/x/sys/unix"
+
+// getSid returns the session ID of the terminal (x/sys has no Getsid
+// on Solaris).
+func getSid(fd uintptr) (int, error) {
+	return unix.IoctlGetInt(int(fd), unix.TIOCGSID)
+}
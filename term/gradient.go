@@ -0,0 +1,144 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"math"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// GradientStyle colors the characters of a text with a gradient from
+// the color Start to the color End (see Gradient). The other attributes
+// of Style (except Fg) are applied to the whole text.
+type GradientStyle struct {
+	Start, End Color
+	Style
+}
+
+// Gradient returns a GradientStyle for a gradient from the color start to
+// the color end, e.g. for headers. The colors are true colors if
+// the environment variable COLORTERM is truecolor or 24bit, otherwise they
+// are converted to the nearest colors of the 256 color palette.
+//   fmt.Println(term.Gradient(term.RGB(255, 0, 0), term.RGB(0, 0, 255)).Render("Header"))
+func Gradient(start, end Color) GradientStyle {
+	return GradientStyle{Start: start, End: end}
+}
+
+// At returns the color at t (0: Start, 1: End), e.g. for the filled part
+// of a progress bar.
+func (g GradientStyle) At(t float64) Color {
+	t = math.Max(0, math.Min(1, t))
+	r1, g1, b1 := g.Start.rgb()
+	r2, g2, b2 := g.End.rgb()
+	mix := func(x, y uint8) uint8 {
+		return uint8(float64(x) + (float64(y)-float64(x))*t + 0.5)
+	}
+	return fitColor(mix(r1, r2), mix(g1, g2), mix(b1, b2))
+}
+
+// Render returns s with the gradient spread over its characters (white
+// space is not colored).
+func (g GradientStyle) Render(s string) string {
+	n := utf8.RuneCountInString(s)
+	i := 0
+	return g.render(s, func(rune) float64 {
+		t := 0.0
+		if n > 1 {
+			t = float64(i) / float64(n-1)
+		}
+		i++
+		return t
+	})
+}
+
+// renderColumns is like Render, but the gradient is spread over width
+// columns, so that lines with the same width get the same colors
+// in each column.
+func (g GradientStyle) renderColumns(s string, width int) string {
+	col := 0
+	return g.render(s, func(ch rune) float64 {
+		t := 0.0
+		if width > 1 {
+			t = float64(col) / float64(width-1)
+		}
+		col += RuneWidth(ch)
+		return t
+	})
+}
+
+// render returns s with the color at pos(ch) for each character ch.
+func (g GradientStyle) render(s string, pos func(ch rune) float64) string {
+	var sb strings.Builder
+	for _, ch := range s {
+		t := pos(ch)
+		if ch == ' ' || ch == '\t' {
+			sb.WriteRune(ch)
+			continue
+		}
+		style := g.Style
+		style.Fg = g.At(t)
+		sb.WriteString(style.Render(string(ch)))
+	}
+	return sb.String()
+}
+
+// Rainbow returns s with the colors of the rainbow spread over its
+// characters (see Gradient).
+func Rainbow(s string) string {
+	n := utf8.RuneCountInString(s)
+	var sb strings.Builder
+	i := 0
+	for _, ch := range s {
+		if ch == ' ' || ch == '\t' {
+			sb.WriteRune(ch)
+		} else {
+			hue := 0.0
+			if n > 1 {
+				// from red to violet
+				hue = 300 * float64(i) / float64(n-1)
+			}
+			sb.WriteString(Style{Fg: hueColor(hue)}.Render(string(ch)))
+		}
+		i++
+	}
+	return sb.String()
+}
+
+// hueColor returns the fully saturated color with the given hue (0-360).
+func hueColor(hue float64) Color {
+	x := 1 - math.Abs(math.Mod(hue/60, 2)-1)
+	var r, g, b float64
+	switch {
+	case hue < 60:
+		r, g = 1, x
+	case hue < 120:
+		r, g = x, 1
+	case hue < 180:
+		g, b = 1, x
+	case hue < 240:
+		g, b = x, 1
+	case hue < 300:
+		r, b = x, 1
+	default:
+		r, b = 1, x
+	}
+	return fitColor(uint8(r*255+0.5), uint8(g*255+0.5), uint8(b*255+0.5))
+}
+
+// fitColor returns the true color or the nearest color of the 256 color
+// palette if the terminal does not support true colors.
+func fitColor(r, g, b uint8) Color {
+	if trueColor() {
+		return RGB(r, g, b)
+	}
+	return Index(nearest256(r, g, b))
+}
+
+// trueColor reports whether the terminal supports true colors according
+// to the environment variable COLORTERM.
+func trueColor() bool {
+	ct := os.Getenv("COLORTERM")
+	return ct == "truecolor" || ct == "24bit"
+}
@@ -0,0 +1,165 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	zwj  = 0x200D // zero width joiner
+	zwnj = 0x200C // zero width non-joiner
+)
+
+// Graphemes splits s into grapheme clusters (user-perceived characters)
+// following the rules of Unicode Standard Annex #29 for extended grapheme
+// clusters with these simplifications: there are no prepend characters
+// and the extended pictographic characters are approximated by the emoji
+// blocks.
+//   term.Graphemes("é👍🏽🇩🇪") -> ["é" "👍🏽" "🇩🇪"]
+func Graphemes(s string) []string {
+	var clusters []string
+	for s != "" {
+		n := graphemeLen([]byte(s))
+		clusters = append(clusters, s[:n])
+		s = s[n:]
+	}
+	return clusters
+}
+
+// graphemeLen returns the length in bytes of the first grapheme cluster in b.
+func graphemeLen(b []byte) int {
+	prev, pos := utf8.DecodeRune(b)
+	if pos == 0 {
+		return 0
+	}
+	ri := 0 // number of regional indicators in a row
+	if isRegional(prev) {
+		ri = 1
+	}
+	// an extended pictographic character followed by extend characters
+	pict := isPictographic(prev)
+	for pos < len(b) {
+		r, n := utf8.DecodeRune(b[pos:])
+		if !continuesCluster(prev, r, ri, pict) {
+			break
+		}
+		if isRegional(r) {
+			ri++
+		}
+		if isPictographic(r) {
+			pict = true
+		} else if !isExtend(r) && r != zwj {
+			pict = false
+		}
+		prev = r
+		pos += n
+	}
+	return pos
+}
+
+// lastGraphemeLen returns the length in bytes of the last grapheme cluster in b.
+func lastGraphemeLen(b []byte) int {
+	n := 0
+	for len(b) > 0 {
+		n = graphemeLen(b)
+		b = b[n:]
+	}
+	return n
+}
+
+// continuesCluster returns true if there is no grapheme cluster boundary
+// between prev and r.
+func continuesCluster(prev, r rune, ri int, pict bool) bool {
+	switch {
+	case prev == '\r' && r == '\n': // GB3
+		return true
+	case isGraphemeControl(prev) || isGraphemeControl(r): // GB4, GB5
+		return false
+	}
+	lp, lr := hangulType(prev), hangulType(r)
+	switch {
+	case lp == 'L' && (lr == 'L' || lr == 'V' || lr == 'S' || lr == 'X'): // GB6
+		return true
+	case (lp == 'S' || lp == 'V') && (lr == 'V' || lr == 'T'): // GB7 (S: LV syllable)
+		return true
+	case (lp == 'X' || lp == 'T') && lr == 'T': // GB8 (X: LVT syllable)
+		return true
+	case isExtend(r) || r == zwj || unicode.Is(unicode.Mc, r): // GB9, GB9a
+		return true
+	case prev == zwj && pict && isPictographic(r): // GB11
+		return true
+	case isRegional(prev) && isRegional(r): // GB12, GB13
+		return ri%2 == 1
+	}
+	return false
+}
+
+func isGraphemeControl(r rune) bool {
+	return unicode.IsControl(r) || r == 0x2028 || r == 0x2029
+}
+
+func isExtend(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me) || r == zwnj ||
+		r >= 0xFE00 && r <= 0xFE0F || // variation selectors
+		r >= 0x1F3FB && r <= 0x1F3FF || // emoji modifiers
+		r >= 0xE0020 && r <= 0xE007F || // tags
+		r >= 0xE0100 && r <= 0xE01EF
+}
+
+func isRegional(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+func isPictographic(r rune) bool {
+	switch {
+	case r < 0xA9:
+		return false
+	case r == 0xA9, r == 0xAE, r == 0x203C, r == 0x2049, r == 0x2122, r == 0x2139,
+		r >= 0x2194 && r <= 0x21AA, r >= 0x231A && r <= 0x23FF, r >= 0x24C2 && r <= 0x24FF,
+		r >= 0x25AA && r <= 0x27BF, r >= 0x2934 && r <= 0x2935, r >= 0x2B05 && r <= 0x2B55,
+		r == 0x3030, r == 0x303D, r == 0x3297, r == 0x3299,
+		r >= 0x1F000 && r <= 0x1FAFF && !isRegional(r) && !(r >= 0x1F3FB && r <= 0x1F3FF):
+		return true
+	}
+	return false
+}
+
+// hangulType returns the Hangul syllable type of r: 'L', 'V', 'T',
+// 'S' (LV syllable), 'X' (LVT syllable) or 0.
+func hangulType(r rune) byte {
+	switch {
+	case r >= 0x1100 && r <= 0x115F, r >= 0xA960 && r <= 0xA97C:
+		return 'L'
+	case r >= 0x1160 && r <= 0x11A7, r >= 0xD7B0 && r <= 0xD7C6:
+		return 'V'
+	case r >= 0x11A8 && r <= 0x11FF, r >= 0xD7CB && r <= 0xD7FB:
+		return 'T'
+	case r >= 0xAC00 && r <= 0xD7A3:
+		if (r-0xAC00)%28 == 0 {
+			return 'S'
+		}
+		return 'X'
+	}
+	return 0
+}
+
+// clusterWidth returns the number of columns a grapheme cluster occupies:
+// the width of its widest rune; emoji presentation (U+FE0F) makes
+// a pictographic character wide and a pair of regional indicators (a flag)
+// is wide too.
+func clusterWidth(b []byte) int {
+	w := 0
+	first, n := utf8.DecodeRune(b)
+	if isRegional(first) && len(b) > n {
+		return 2
+	}
+	for _, r := range string(b) {
+		if r == 0xFE0F && isPictographic(first) {
+			w = maxInt(w, 2)
+		}
+		w = maxInt(w, RuneWidth(r))
+	}
+	return w
+}
@@ -0,0 +1,159 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ANSI escape codes: enter/leave the alternate screen (ESC[?1049h/l).
+const (
+	altScreenOn  = "\x1b[?1049h"
+	altScreenOff = "\x1b[?1049l"
+)
+
+// specialKeyNames are the names of the keys KeyUp ... KeyF12.
+var specialKeyNames = []string{
+	"Up", "Down", "Right", "Left", "Home", "End", "Insert", "Delete", "PageUp", "PageDown",
+	"F1", "F2", "F3", "F4", "F5", "F6", "F7", "F8", "F9", "F10", "F11", "F12",
+}
+
+// keyName returns a readable name of a key of a key map (e.g. "^A",
+// "Alt-F" or "Ctrl-Left").
+func keyName(key string) string {
+	ev := Event{Key: []byte(key)}
+	ev.decodeKey()
+	var b strings.Builder
+	for _, m := range []struct {
+		mod  Modifiers
+		name string
+	}{{ModCtrl, "Ctrl-"}, {ModAlt, "Alt-"}, {ModShift, "Shift-"}, {ModSuper, "Super-"}} {
+		if ev.Mod&m.mod != 0 {
+			b.WriteString(m.name)
+		}
+	}
+	switch c := ev.Code; {
+	case c == 0:
+		return fmt.Sprintf("%q", key)
+	case c >= KeyUp && c <= KeyF12:
+		b.WriteString(specialKeyNames[c-KeyUp])
+	case c == 0x7F:
+		b.WriteString("Backspace")
+	case c == escape:
+		b.WriteString("Esc")
+	case c == '\t':
+		b.WriteString("Tab")
+	case c == ' ':
+		b.WriteString("Space")
+	case c < 0x20:
+		b.WriteString("^" + string(c+'@'))
+	default:
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// bindingLines returns the lines of the help for the key map: the name
+// of each bound action followed by its keys.
+func bindingLines(m KeyMap) []string {
+	keys := map[EditAction][]string{}
+	type binding struct {
+		action EditAction
+		name   string
+	}
+	seen := map[binding]bool{}
+	for k, a := range m {
+		// different sequences may have the same name (e.g. Home)
+		if b := (binding{a, keyName(k)}); !seen[b] {
+			seen[b] = true
+			keys[a] = append(keys[a], b.name)
+		}
+	}
+	names := map[EditAction]string{}
+	for name, a := range editActionNames {
+		names[a] = name
+	}
+	var lines []string
+	for a := EditAction(1); int(a) <= len(editActionNames); a++ {
+		if len(keys[a]) > 0 {
+			sort.Strings(keys[a])
+			lines = append(lines, fmt.Sprintf("%-24s %s", names[a], strings.Join(keys[a], ", ")))
+		}
+	}
+	return lines
+}
+
+// bindingsView is the state of the key bindings shown by showBindings.
+type bindingsView struct {
+	lines []string
+	query []rune
+}
+
+// showBindings shows the key bindings of the key map in the alternate
+// screen until Esc, Enter, "?" or the key of dump-functions is typed;
+// typed characters search for the actions and keys that contain them.
+// outMu must be locked; it is unlocked while waiting for a key, so that
+// other goroutines can print (see writeAbove).
+func (r *reader) showBindings() {
+	r.bindings = &bindingsView{lines: bindingLines(keyMap)}
+	out.WriteString(altScreenOn)
+	defer func() {
+		r.bindings = nil
+		out.WriteString(altScreenOff)
+		out.Flush()
+	}()
+	for {
+		r.drawBindings()
+		out.Flush()
+		outMu.Unlock()
+		key, err := r.readKey()
+		outMu.Lock()
+		if err != nil {
+			// the error is returned by the next read
+			return
+		}
+		v := r.bindings
+		ch := []rune(string(key))[0]
+		switch {
+		case keyMap[string(key)] == EditDumpFunctions, ch == escape && len(key) == 1,
+			ch == '\r', ch == '\n', ch == '?' && len(v.query) == 0:
+			return
+		case ch == 0x7F || ch == '\b':
+			if len(v.query) > 0 {
+				v.query = v.query[:len(v.query)-1]
+			}
+		case unicode.IsPrint(ch) && len(key) == utf8.RuneLen(ch):
+			v.query = append(v.query, ch)
+		}
+	}
+}
+
+// drawBindings draws the key bindings that match the query in the
+// alternate screen.
+func (r *reader) drawBindings() {
+	v := r.bindings
+	_, height := getTermSize()
+	out.WriteString(clearScreen)
+	var shown, more int
+	q := strings.ToLower(string(v.query))
+	for _, l := range v.lines {
+		if !strings.Contains(strings.ToLower(l), q) {
+			continue
+		}
+		if shown < height-2 {
+			out.WriteString("\n" + l)
+			shown++
+		} else {
+			more++
+		}
+	}
+	if more > 0 {
+		fmt.Fprintf(out, "\n(+%d)", more)
+	}
+	out.WriteString("\x1b[H")
+	fmt.Fprintf(out, msg(MsgKeyBindings), string(v.query))
+}
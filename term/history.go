@@ -0,0 +1,200 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"bytes"
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/sys/unix"
+)
+
+// History is a list of input lines that can be recalled with Up/Down (^P/^N)
+// and searched with ^R. Lines submitted with Enter are added to it by the input
+// functions if it is set in InputOpt.History and the input is echoed normally.
+type History struct {
+	lines []string
+	max   int
+}
+
+// NewHistory returns a new History with the given lines that keeps at most
+// max lines (unlimited if max is 0).
+func NewHistory(max int, lines ...string) *History {
+	h := &History{max: max}
+	for _, line := range lines {
+		h.Add(line)
+	}
+	return h
+}
+
+// Add adds a line to the history. Empty lines and lines that are equal
+// to the last line are not added.
+func (h *History) Add(line string) {
+	if line == "" || len(h.lines) > 0 && h.lines[len(h.lines)-1] == line {
+		return
+	}
+	h.lines = append(h.lines, line)
+	if h.max > 0 && len(h.lines) > h.max {
+		h.lines = h.lines[len(h.lines)-h.max:]
+	}
+}
+
+// Lines returns the lines in the history (the oldest first).
+func (h *History) Lines() []string {
+	return append([]string(nil), h.lines...)
+}
+
+// history returns the history of the reader or nil if it is not used.
+func (r *reader) history() *History {
+	if r.opt.Echo != EchoNormal {
+		return nil
+	}
+	return r.opt.History
+}
+
+// setBuf replaces the input and moves the cursor to the end.
+func (r *reader) setBuf(b []byte) {
+	r.buf = b
+	r.pos = len(b)
+	r.render()
+}
+
+// historyMove moves delta lines forward (> 0) or back (< 0) in the history.
+func (r *reader) historyMove(delta int) {
+	h := r.history()
+	if h == nil {
+		return
+	}
+	i := r.histIndex + delta
+	if i < 0 || i > len(h.lines) {
+		out.WriteString(bell)
+		return
+	}
+	if r.histIndex == len(h.lines) {
+		r.histSaved = append([]byte{}, r.buf...)
+	}
+	r.histIndex = i
+	if i == len(h.lines) {
+		r.setBuf(r.histSaved)
+	} else {
+		r.setBuf([]byte(h.lines[i]))
+	}
+	r.viClamp()
+}
+
+// historySearch is the state of an incremental reverse search.
+type historySearch struct {
+	query   []byte
+	index   int  // index of the matching line
+	failed  bool // no line matches the query
+	oldBuf  []byte
+	oldPos  int
+	histLen int
+}
+
+func (r *reader) startSearch() {
+	h := r.history()
+	if h == nil {
+		return
+	}
+	r.search = &historySearch{
+		index:   len(h.lines),
+		oldBuf:  append([]byte{}, r.buf...),
+		oldPos:  r.pos,
+		histLen: len(h.lines),
+	}
+	r.render()
+}
+
+// searchFrom searches the history for the query starting at index start
+// going back to older lines.
+func (r *reader) searchFrom(start int) {
+	s := r.search
+	lines := r.opt.History.lines
+	if start >= len(lines) {
+		start = len(lines) - 1
+	}
+	for i := start; i >= 0; i-- {
+		if j := bytes.Index([]byte(lines[i]), s.query); j >= 0 {
+			s.index, s.failed = i, false
+			r.buf = []byte(lines[i])
+			r.pos = j
+			return
+		}
+	}
+	s.failed = true
+	out.WriteString(bell)
+}
+
+// endSearch leaves the search mode; if restore is true, the input
+// before the search is restored, otherwise the matching line is kept.
+func (r *reader) endSearch(restore bool) {
+	s := r.search
+	r.search = nil
+	if restore {
+		r.buf, r.pos = s.oldBuf, s.oldPos
+	} else if s.index < s.histLen {
+		r.histIndex = s.index
+	}
+	r.render()
+}
+
+// handleSearch processes a key during an incremental reverse search:
+// typed characters are added to the query, ERASE removes the last one,
+// the key bound to EditReverseSearchHistory searches for the next match,
+// ^G aborts the search. Other keys end the search and are processed as
+// usual (with the matching line as input). It returns true for handled
+// if the key was processed.
+func (r *reader) handleSearch(key []byte) (handled bool) {
+	s := r.search
+	switch {
+	case string(key) == "\x07":
+		r.endSearch(true)
+		return true
+	case keyMap[string(key)] == EditReverseSearchHistory:
+		if len(s.query) > 0 {
+			r.searchFrom(s.index - 1)
+		}
+	case len(key) == 1 && (key[0] == r.raw.Cc[unix.VERASE] || key[0] == 0x7F || key[0] == 0x08):
+		if len(s.query) == 0 {
+			break
+		}
+		_, n := utf8.DecodeLastRune(s.query)
+		s.query = s.query[:len(s.query)-n]
+		if len(s.query) == 0 {
+			s.failed = false
+			r.buf, r.pos = s.oldBuf, s.oldPos
+			s.index = s.histLen
+		} else {
+			r.searchFrom(s.histLen - 1)
+		}
+	default:
+		ch, _ := utf8.DecodeRune(key)
+		if key[0] == escape || unicode.IsControl(ch) {
+			r.endSearch(false)
+			return false
+		}
+		s.query = append(s.query, key...)
+		r.searchFrom(s.index)
+	}
+	r.render()
+	return true
+}
+
+// renderSearch prints the search prompt and the matching line
+// with the cursor at the match.
+func (r *reader) renderSearch() {
+	s := r.search
+	format := msg(MsgSearch)
+	if s.failed {
+		format = msg(MsgSearchFailed)
+	}
+	prompt := fmt.Sprintf(format, s.query)
+	out.WriteString(prompt)
+	cols, _ := getTermSize()
+	r.cursorRow = screenRows(textWidth(prompt)+bytesWidth(r.buf[:r.pos]), cols) - 1
+	out.Write(r.buf)
+	cursorBack(out, bytesWidth(r.buf[r.pos:]))
+}
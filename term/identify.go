@@ -0,0 +1,85 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import "sync"
+
+// ANSI escape code: Secondary Device Attributes (DA2) ESC[>c
+const secondaryDA = "\x1b[>c"
+
+// Identity contains the device attributes reported by the terminal.
+type Identity struct {
+	Level    int    // conformance level from DA1 (e.g. 1: VT100, 62: VT220, 64: VT420)
+	Features []int  // extensions from DA1 (e.g. 4: sixel graphics, 22: ANSI color)
+	Type     int    // terminal type from DA2 (-1 if DA2 is not supported)
+	Version  int    // firmware or program version from DA2
+	Name     string // name of the emulator if it is known
+}
+
+// HasFeature returns whether the terminal reported the extension f in
+// its primary device attributes.
+func (id *Identity) HasFeature(f int) bool {
+	for _, x := range id.Features {
+		if x == f {
+			return true
+		}
+	}
+	return false
+}
+
+// emulatorNames maps the terminal types reported in DA2 that are unique
+// to an emulator to its name.
+var emulatorNames = map[int]string{
+	65: "vte",
+	77: "mintty",
+	83: "screen",
+	84: "tmux",
+	85: "rxvt-unicode",
+}
+
+var (
+	identityMu sync.Mutex
+	identity   *Identity
+)
+
+// Identify queries the primary and secondary device attributes
+// (ESC[c and ESC[>c) of the terminal and returns the reported type
+// and version. Name is set for emulators that can be recognized by
+// their type (e.g. "tmux", "screen", "vte") and is empty otherwise.
+// The result is cached and is used by other functions like
+// DetectImageProtocol to special-case terminals. It panics if stdin and
+// stdout are not connected to a terminal and returns ErrNoResponse if
+// the terminal does not respond within the timeout (see SetQueryTimeout).
+func Identify() (*Identity, error) {
+	identityMu.Lock()
+	defer identityMu.Unlock()
+	if identity != nil {
+		return identity, nil
+	}
+	// DA2 is sent first, the response to DA1 marks the end
+	resp, err := queryDA(secondaryDA)
+	if err != nil {
+		return nil, err
+	}
+	id := &Identity{Type: -1}
+	if da1, _ := parseCSI(resp, '?', 'c'); len(da1) > 0 {
+		id.Level, id.Features = da1[0], da1[1:]
+	}
+	if da2, ok := parseCSI(resp, '>', 'c'); ok {
+		id.Type = da2[0]
+		if len(da2) > 1 {
+			id.Version = da2[1]
+		}
+		id.Name = emulatorNames[id.Type]
+	}
+	identity = id
+	return id, nil
+}
+
+// cachedIdentity returns the result of Identify if it was called
+// successfully or nil.
+func cachedIdentity() *Identity {
+	identityMu.Lock()
+	defer identityMu.Unlock()
+	return identity
+}
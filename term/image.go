@@ -0,0 +1,267 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/png"
+	"os"
+	"strings"
+)
+
+// ImageProtocol is a protocol for displaying images in a terminal.
+type ImageProtocol uint8
+
+const (
+	ImageAuto   ImageProtocol = iota // detect the protocol (see DetectImageProtocol)
+	ImageKitty                       // kitty graphics protocol
+	ImageITerm2                      // iTerm2 inline images (OSC 1337)
+	ImageSixel                       // DEC sixel graphics
+	ImageBlocks                      // colored half blocks (works with all truecolor terminals)
+)
+
+// ImageOpt contains the options for the ShowImage function.
+// If Width and Height are both 0, the width of the image in cells is used,
+// but at most the width of the terminal. If only one of them is 0, it is
+// computed from the other one, so that the aspect ratio is preserved.
+type ImageOpt struct {
+	Width    int           // width in character cells
+	Height   int           // height in character cells
+	Protocol ImageProtocol // default: ImageAuto
+}
+
+// DetectImageProtocol returns the protocol for displaying images that
+// the terminal probably supports; the detection is based on the environment
+// variables TERM, TERM_PROGRAM and KITTY_WINDOW_ID and, if Identify was
+// called, on the reported sixel support. If no other protocol is detected,
+// ImageBlocks is returned.
+func DetectImageProtocol() ImageProtocol {
+	term := os.Getenv("TERM")
+	prog := os.Getenv("TERM_PROGRAM")
+	switch {
+	case os.Getenv("KITTY_WINDOW_ID") != "" || term == "xterm-kitty" || prog == "ghostty":
+		return ImageKitty
+	case prog == "iTerm.app" || prog == "WezTerm":
+		return ImageITerm2
+	case strings.Contains(term, "sixel") || term == "mlterm" || term == "foot" ||
+		strings.HasPrefix(term, "foot-") || term == "yaft-256color":
+		return ImageSixel
+	}
+	if id := cachedIdentity(); id != nil && id.HasFeature(4) {
+		return ImageSixel
+	}
+	return ImageBlocks
+}
+
+// ShowImage displays the image img inline at the cursor position. After
+// the image the cursor is at the start of the next line. opt may be nil.
+// It returns an error if stdout is not connected to a terminal.
+func ShowImage(img image.Image, opt *ImageOpt) error {
+	if opt == nil {
+		opt = &ImageOpt{}
+	}
+	termWidth, _, err := Size(false)
+	if err != nil {
+		return err
+	}
+	outMu.Lock()
+	f := outFile
+	outMu.Unlock()
+	cellW, cellH, _ := CellSize(f.Fd())
+	if cellW == 0 || cellH == 0 {
+		cellW, cellH = 10, 20
+	}
+	cols, rows := imageCells(img.Bounds(), opt.Width, opt.Height, int(termWidth), int(cellW), int(cellH))
+	protocol := opt.Protocol
+	if protocol == ImageAuto {
+		protocol = DetectImageProtocol()
+	}
+	var b bytes.Buffer
+	switch protocol {
+	case ImageKitty:
+		err = writeKitty(&b, img, cols, rows)
+	case ImageITerm2:
+		err = writeITerm2(&b, img, cols, rows)
+	case ImageSixel:
+		writeSixel(&b, scaleImage(img, cols*int(cellW), rows*int(cellH)))
+	default:
+		writeBlocks(&b, scaleImage(img, cols, rows*2))
+	}
+	if err != nil {
+		return err
+	}
+	b.WriteByte('\n')
+	writeOut(func() { out.Write(b.Bytes()) })
+	return nil
+}
+
+// imageCells returns the size of the image in character cells.
+func imageCells(bounds image.Rectangle, cols, rows, maxCols, cellW, cellH int) (int, int) {
+	imgW, imgH := bounds.Dx(), bounds.Dy()
+	if imgW == 0 || imgH == 0 {
+		return 1, 1
+	}
+	// aspect ratio of the image in cells
+	ratio := float64(imgH*cellW) / float64(imgW*cellH)
+	switch {
+	case cols == 0 && rows == 0:
+		cols = (imgW + cellW - 1) / cellW
+		if maxCols > 0 && cols > maxCols {
+			cols = maxCols
+		}
+		rows = int(float64(cols)*ratio + 0.5)
+	case cols == 0:
+		cols = int(float64(rows)/ratio + 0.5)
+	case rows == 0:
+		rows = int(float64(cols)*ratio + 0.5)
+	}
+	return maxInt(cols, 1), maxInt(rows, 1)
+}
+
+// scaleImage scales img to the size w x h (nearest neighbor).
+func scaleImage(img image.Image, w, h int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	src := img.Bounds()
+	for y := 0; y < h; y++ {
+		sy := src.Min.Y + y*src.Dy()/h
+		for x := 0; x < w; x++ {
+			sx := src.Min.X + x*src.Dx()/w
+			dst.Set(x, y, img.At(sx, sy))
+		}
+	}
+	return dst
+}
+
+func encodePNG(img image.Image) (string, error) {
+	var b bytes.Buffer
+	if err := png.Encode(&b, img); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b.Bytes()), nil
+}
+
+// writeKitty writes the image using the kitty graphics protocol; the data
+// is transmitted in chunks of 4096 bytes.
+func writeKitty(b *bytes.Buffer, img image.Image, cols, rows int) error {
+	data, err := encodePNG(img)
+	if err != nil {
+		return err
+	}
+	for first := true; ; first = false {
+		chunk := data
+		if len(chunk) > 4096 {
+			chunk = chunk[:4096]
+		}
+		data = data[len(chunk):]
+		more := 0
+		if data != "" {
+			more = 1
+		}
+		if first {
+			fmt.Fprintf(b, "\x1b_Gf=100,a=T,c=%d,r=%d,m=%d;%s\x1b\\", cols, rows, more, chunk)
+		} else {
+			fmt.Fprintf(b, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+		if more == 0 {
+			return nil
+		}
+	}
+}
+
+func writeITerm2(b *bytes.Buffer, img image.Image, cols, rows int) error {
+	data, err := encodePNG(img)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(b, "\x1b]1337;File=inline=1;width=%d;height=%d;preserveAspectRatio=0:%s\a",
+		cols, rows, data)
+	return nil
+}
+
+// writeSixel writes the image as sixel graphics with the web safe palette.
+func writeSixel(b *bytes.Buffer, img *image.RGBA) {
+	bounds := img.Bounds()
+	pimg := image.NewPaletted(bounds, palette.WebSafe)
+	draw.FloydSteinberg.Draw(pimg, bounds, img, image.Point{})
+	w, h := bounds.Dx(), bounds.Dy()
+	fmt.Fprintf(b, "\x1bPq\"1;1;%d;%d", w, h)
+	for i, c := range palette.WebSafe {
+		r, g, bl, _ := c.RGBA()
+		fmt.Fprintf(b, "#%d;2;%d;%d;%d", i, r*100/0xFFFF, g*100/0xFFFF, bl*100/0xFFFF)
+	}
+	band := make([]byte, w)
+	for y0 := 0; y0 < h; y0 += 6 {
+		used := make(map[uint8]bool)
+		for y := y0; y < y0+6 && y < h; y++ {
+			for x := 0; x < w; x++ {
+				used[pimg.ColorIndexAt(x, y)] = true
+			}
+		}
+		first := true
+		for idx := 0; idx < len(palette.WebSafe); idx++ {
+			if !used[uint8(idx)] {
+				continue
+			}
+			for x := 0; x < w; x++ {
+				var bits byte
+				for dy := 0; dy < 6 && y0+dy < h; dy++ {
+					if pimg.ColorIndexAt(x, y0+dy) == uint8(idx) {
+						bits |= 1 << dy
+					}
+				}
+				band[x] = '?' + bits
+			}
+			if !first {
+				b.WriteByte('$')
+			}
+			first = false
+			fmt.Fprintf(b, "#%d", idx)
+			writeSixelRLE(b, band)
+		}
+		b.WriteByte('-')
+	}
+	b.WriteString("\x1b\\")
+}
+
+// writeSixelRLE writes the sixel characters with run-length encoding.
+func writeSixelRLE(b *bytes.Buffer, band []byte) {
+	for i := 0; i < len(band); {
+		j := i + 1
+		for j < len(band) && band[j] == band[i] {
+			j++
+		}
+		if n := j - i; n > 3 {
+			fmt.Fprintf(b, "!%d%c", n, band[i])
+		} else {
+			b.Write(band[i:j])
+		}
+		i = j
+	}
+}
+
+// writeBlocks writes the image with upper half blocks; each character
+// cell shows two pixels (foreground: upper, background: lower).
+func writeBlocks(b *bytes.Buffer, img *image.RGBA) {
+	bounds := img.Bounds()
+	for y := 0; y < bounds.Dy(); y += 2 {
+		if y > 0 {
+			b.WriteByte('\n')
+		}
+		for x := 0; x < bounds.Dx(); x++ {
+			top := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+			bottom := top
+			if y+1 < bounds.Dy() {
+				bottom = color.RGBAModel.Convert(img.At(x, y+1)).(color.RGBA)
+			}
+			fmt.Fprintf(b, "\x1b[38;2;%d;%d;%dm\x1b[48;2;%d;%d;%dm▀",
+				top.R, top.G, top.B, bottom.R, bottom.G, bottom.B)
+		}
+		b.WriteString("\x1b[0m")
+	}
+}
@@ -0,0 +1,106 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/sys/unix"
+)
+
+var (
+	injectOnce sync.Once
+	injectMu   sync.Mutex // serializes the goroutines of InjectKeys
+	injectPipe = [2]int{-1, -1}
+	injecting  int32
+)
+
+// InjectKeys feeds the keys in seq to the input functions of this package
+// as if they were typed, one key (a rune or an escape sequence) after each
+// delay. It returns at once; the keys are injected while the program runs,
+// e.g. for self-running demos and tutorials. Keys typed by the user are
+// still read. The keys of several calls are injected one call after
+// the other.
+//   term.InjectKeys("Alice\r", 200*time.Millisecond)
+//   term.Input("Name: ", &name, nil)
+func InjectKeys(seq string, delay time.Duration) {
+	injectOnce.Do(func() {
+		var p [2]int
+		if err := unix.Pipe(p[:]); err != nil {
+			return
+		}
+		unix.SetNonblock(p[0], true)
+		injectPipe = p
+		atomic.StoreInt32(&injecting, 1)
+	})
+	if atomic.LoadInt32(&injecting) == 0 {
+		return
+	}
+	go func() {
+		injectMu.Lock()
+		defer injectMu.Unlock()
+		for _, key := range splitKeys(seq) {
+			time.Sleep(delay)
+			if key == "\r" {
+				// translated like typed input
+				if t, err := unix.IoctlGetTermios(int(inFile.Fd()), termiosGet); err == nil && t.Iflag&unix.ICRNL != 0 {
+					key = "\n"
+				}
+			}
+			unix.Write(injectPipe[1], []byte(key))
+		}
+	}()
+}
+
+// splitKeys splits seq into keys like keyReader.readKey.
+func splitKeys(seq string) []string {
+	var keys []string
+	for seq != "" {
+		n := 1
+		switch {
+		case strings.HasPrefix(seq, pasteStart):
+			if i := strings.Index(seq, pasteEnd); i >= 0 {
+				n = i + len(pasteEnd)
+			} else {
+				n = len(seq)
+			}
+		case strings.HasPrefix(seq, "\x1b["):
+			n = len(seq)
+			for i := 2; i < len(seq); i++ {
+				if c := seq[i]; c >= 0x40 && c <= 0x7E {
+					n = i + 1
+					break
+				}
+			}
+		case strings.HasPrefix(seq, "\x1bO"):
+			n = minInt(3, len(seq))
+		case seq[0] == escape && len(seq) > 1:
+			_, size := utf8.DecodeRuneInString(seq[1:])
+			n = 1 + size
+		case seq[0] != escape:
+			_, n = utf8.DecodeRuneInString(seq)
+		}
+		keys = append(keys, seq[:n])
+		seq = seq[n:]
+	}
+	return keys
+}
+
+// readInjected reads injected keys into r.pending; it returns true if
+// there were any.
+func (r *keyReader) readInjected() bool {
+	b := make([]byte, 64)
+	found := false
+	for {
+		n, err := unix.Read(injectPipe[0], b)
+		if n <= 0 || err != nil {
+			return found
+		}
+		r.pending = append(r.pending, b[:n]...)
+		found = true
+	}
+}
@@ -3,12 +3,14 @@
 package term
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
-	"os"
+	"io"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 	"unicode/utf8"
 )
@@ -16,35 +18,127 @@ import (
 // Options for Input function.
 // If ConvFunc is used it must return an error if the input value
 // cannot be converted.
+//
+// ISIG, IXON and IEXTEN control the terminal modes while the input is read.
+// By default ISIG is disabled and ^C, ^\ and ^Z are handled by the input
+// functions, which restore the terminal state before a signal is sent
+// (see Interrupt). If it is enabled, the terminal sends the signals itself
+// and the program should use RestoreOnExit. IXON is disabled by default
+// only if ^S or ^Q are bound in the key map (see SetKeyMap); IEXTEN is
+// not changed by default.
+//
+// Highlight is called with the input after each key if the input is echoed
+// normally; the texts of the returned segments must add up to the input
+// (otherwise it is printed without styles). It is called while the output
+// of this package is locked, so it must not print with this package (e.g.
+// Println or a SafeWriter), which would deadlock:
+//   opt.Highlight = func(s string) []term.StyledSegment {
+//   	if _, err := strconv.Atoi(s); err != nil {
+//   		return []term.StyledSegment{{Text: s, Style: term.Style{Fg: term.Red}}}
+//   	}
+//   	return []term.StyledSegment{{Text: s}}
+//   }
 type InputOpt struct {
-	Default  interface{}                       // optional
-	Echo     EchoMode                          // default: EchoNormal
-	Limit    uint8                             // see function GetBytes
-	ConvFunc func(string) (interface{}, error) // optional
+	Default         interface{}                       // optional
+	Echo            EchoMode                          // default: EchoNormal
+	Limit           uint                              // see function GetBytes
+	NoAutoSubmit    bool                              // if Limit is reached, further typing is blocked
+	ConvFunc        func(string) (interface{}, error) // optional
+	Key             string                            // optional, see function SetAnswers
+	Interrupt       InterruptPolicy                   // what to do if ^C is typed
+	OnInterrupt     func()                            // optional, called unless ^C is ignored or inserted
+	Control         ControlPolicy                     // how to handle control characters
+	AllowedControls string                            // control characters that are inserted (e.g. "\t")
+	MaxLen          uint                              // max. number of bytes; further typing is blocked
+	ShowCount       bool                              // show number of bytes and MaxLen after the input
+	PunctWordBreak  bool                              // ^W also stops at punctuation and symbols
+	Editing         EditingMode                       // see function SetEditingMode
+	History         *History                          // optional
+	Placeholder     string                            // hint shown (Theme.Muted) while the input is empty
+	Locale          *Locale                           // optional, for *int, *int64, *uint and *float64
+	Timeout         time.Duration                     // max. time for the input
+	OnTimeout       TimeoutPolicy                     // what to do if Timeout expires
+	NoRetry         bool                              // return a ConversionError instead of showing the prompt again
+	KeepInvalid     bool                              // keep an invalid input visible with the error and edit it again
+	ISIG            TermMode                          // signals sent by the terminal (default: off)
+	IXON            TermMode                          // flow control with ^S and ^Q
+	IEXTEN          TermMode                          // extended input processing (e.g. ^V)
+	Terminators     string                            // characters that end the input like Enter (e.g. ";" or "\x1b")
+	Highlight       func(string) []StyledSegment      // colors the echoed input after each key (must not print)
+	Redact          Redaction                         // replaces the echoed input after it was accepted
+	Tab             TabPolicy                         // how to handle tabs
+	TabWidth        uint                              // distance of the tab stops (default: 8)
+	Complete        func(string) string               // for TabComplete, gets and returns the input before the cursor
+
+	menu bool // "?" shows the key bindings
+}
+
+// ConversionError is returned if an input cannot be converted to the type
+// of the variable and InputOpt.NoRetry is set or in non-interactive mode.
+type ConversionError struct {
+	Input string // the input that could not be converted
+	Err   error  // the error of the conversion
+}
+
+func (e *ConversionError) Error() string {
+	return fmt.Sprintf("invalid input %q: %v", e.Input, e.Err)
+}
+
+// Unwrap returns e.Err.
+func (e *ConversionError) Unwrap() error {
+	return e.Err
 }
 
 // Input gets input from a terminal. The in argument must be the address
 // of a variable to which the input should be assigned. If only enter is
 // typed and there is no default value or if the input cannot be converted
-// to the correct type, the prompt will be shown again.
-// It panics if stdin and stdout are not connected to a terminal or
-// if opt.Default or the return value of opt.ConvFunc are not
-// assignable to *in.
+// to the correct type, the prompt will be shown again (see InputOpt.NoRetry
+// and InputOpt.KeepInvalid).
+// If ^D is typed on an empty input, ErrEOF is returned.
+// It panics if stdin and stdout are not connected to a terminal (unless
+// in non-interactive mode) or if opt.Default or the return value of
+// opt.ConvFunc are not assignable to *in.
 func Input(prompt string, in interface{}, opt *InputOpt) error {
-	checkIsTerminal()
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	return inputAny(prompt, in, opt)
+}
+
+func inputAny(prompt string, in interface{}, opt *InputOpt) error {
+	checkCanInput()
 	if val := reflect.ValueOf(in); val.Kind() != reflect.Ptr {
 		return fmt.Errorf("type of 'in' not a pointer: %s", val.Type())
 	}
 	if opt == nil {
 		opt = &InputOpt{}
 	}
-	var b []byte
+	return input(prompt, in, opt, converter(in, opt))
+}
+
+func input(prompt string, in interface{}, opt *InputOpt, conv func(string) error) error {
+	if isNonInteractive() {
+		return inputAnswer(prompt, in, opt, conv)
+	}
+	shown := prompt
+	if decorated() {
+		shown = decoratePrompt(prompt)
+	}
+	var b, init []byte
 	var s string
 	var err error
 	for {
-		fmt.Print(prompt)
-		b, err = GetBytes(opt.Echo, opt.Limit)
-		fmt.Println()
+		b, _, err = editBytes(shown, init, opt)
+		writeOut(func() { fmt.Fprintln(out) })
+		if err == ErrTimeout {
+			switch {
+			case opt.OnTimeout == TimeoutDefault && opt.Default != nil:
+				setValue(in, opt.Default)
+				s, err = "", nil
+			case opt.OnTimeout == TimeoutSkip:
+				err = ErrSkipped
+			}
+			break
+		}
 		if err != nil {
 			break
 		}
@@ -58,40 +152,213 @@ func Input(prompt string, in interface{}, opt *InputOpt) error {
 				continue
 			}
 		}
-		if opt.ConvFunc == nil {
-			_, err = fmt.Sscan(s, in)
-			if err != nil {
-				resetPrompt()
+		if err = conv(s); err != nil {
+			if opt.NoRetry {
+				err = &ConversionError{s, err}
+				break
+			}
+			if opt.KeepInvalid {
+				showInvalid(shown, opt, b, err)
+				init = b
 				continue
 			}
-			break
-		} else {
+			resetPrompt()
+			continue
+		}
+		break
+	}
+	finishPrompt(prompt, opt, s, err)
+	if err == nil {
+		answered(prompt, opt, s)
+	}
+	return err
+}
+
+// answered is called after an input was accepted; s is empty if
+// the default value was used.
+func answered(prompt string, opt *InputOpt, s string) {
+	recordAnswer(prompt, opt, s)
+	logTranscript(prompt, opt, s)
+}
+
+// converter returns a function that converts the input and assigns
+// the value to *in. For common types of *in fast paths are used
+// instead of fmt.Sscan.
+func converter(in interface{}, opt *InputOpt) func(string) error {
+	if opt.ConvFunc != nil {
+		return func(s string) error {
 			v, err := opt.ConvFunc(s)
 			if err != nil {
-				resetPrompt()
-				continue
+				return err
 			}
 			setValue(in, v)
-			break
+			return nil
 		}
 	}
+	if opt.Locale != nil {
+		if conv := opt.Locale.converter(in); conv != nil {
+			return conv
+		}
+	}
+	switch p := in.(type) {
+	case *string:
+		return func(s string) error {
+			tok, err := firstToken(s)
+			if err == nil {
+				*p = tok
+			}
+			return err
+		}
+	case *int:
+		return func(s string) error {
+			if tok, err := firstToken(s); err == nil && isDecimal(tok, true) {
+				if i, err := strconv.ParseInt(tok, 10, strconv.IntSize); err == nil {
+					*p = int(i)
+					return nil
+				}
+			}
+			return scan(s, in)
+		}
+	case *uint:
+		return func(s string) error {
+			if tok, err := firstToken(s); err == nil && isDecimal(tok, false) {
+				if u, err := strconv.ParseUint(tok, 10, strconv.IntSize); err == nil {
+					*p = uint(u)
+					return nil
+				}
+			}
+			return scan(s, in)
+		}
+	case *float64:
+		return func(s string) error {
+			if tok, err := firstToken(s); err == nil && isDecimalFloat(tok) {
+				if f, err := strconv.ParseFloat(tok, 64); err == nil {
+					*p = f
+					return nil
+				}
+			}
+			return scan(s, in)
+		}
+	case *bool:
+		return func(s string) error {
+			// fmt.Sscan accepts all spellings of strconv.ParseBool
+			if tok, err := firstToken(s); err == nil {
+				if b, err := strconv.ParseBool(tok); err == nil {
+					*p = b
+					return nil
+				}
+			}
+			return scan(s, in)
+		}
+	}
+	return func(s string) error {
+		return scan(s, in)
+	}
+}
+
+func scan(s string, in interface{}) error {
+	_, err := fmt.Sscan(s, in)
 	return err
 }
 
+// isDecimal returns whether tok is a decimal integer without leading zeros
+// (with an optional sign if sign is true), which fmt.Sscan parses like
+// strconv; other tokens (e.g. "0x1f" or "12abc") are left to fmt.Sscan.
+func isDecimal(tok string, sign bool) bool {
+	if sign && tok != "" && (tok[0] == '+' || tok[0] == '-') {
+		tok = tok[1:]
+	}
+	if tok == "" || tok[0] == '0' && len(tok) > 1 {
+		return false
+	}
+	for i := 0; i < len(tok); i++ {
+		if tok[i] < '0' || tok[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isDecimalFloat returns whether tok is a decimal floating-point number
+// with an optional sign, fraction and exponent (e.g. "-1.5e3").
+func isDecimalFloat(tok string) bool {
+	digits := func() int {
+		n := 0
+		for n < len(tok) && tok[n] >= '0' && tok[n] <= '9' {
+			n++
+		}
+		tok = tok[n:]
+		return n
+	}
+	if tok != "" && (tok[0] == '+' || tok[0] == '-') {
+		tok = tok[1:]
+	}
+	n := digits()
+	if tok != "" && tok[0] == '.' {
+		tok = tok[1:]
+		n += digits()
+	}
+	if n == 0 {
+		return false
+	}
+	if tok != "" && (tok[0] == 'e' || tok[0] == 'E') {
+		tok = tok[1:]
+		if tok != "" && (tok[0] == '+' || tok[0] == '-') {
+			tok = tok[1:]
+		}
+		if digits() == 0 {
+			return false
+		}
+	}
+	return tok == ""
+}
+
+// firstToken returns the first space separated token in s like fmt.Sscan.
+func firstToken(s string) (string, error) {
+	s = strings.TrimLeftFunc(s, unicode.IsSpace)
+	if s == "" {
+		return "", io.EOF
+	}
+	if i := strings.IndexFunc(s, unicode.IsSpace); i >= 0 {
+		s = s[:i]
+	}
+	return s, nil
+}
+
 func setValue(in interface{}, v interface{}) {
 	reflect.Indirect(reflect.ValueOf(in)).Set(reflect.ValueOf(v))
 }
 
 // ANSI escape codes: Cursor Up (CUU: ESC[A),
 //                    Cursor Horizontal Absolute (CHA: ESC[G),
-//                    Erase in Line (EL: ESC[K).
+//                    Erase in Display (ED: ESC[J).
 
+// resetPrompt moves the cursor to the start of the prompt of the last
+// input, which may have been wrapped, and erases it.
 func resetPrompt() {
-	fmt.Print("\x1b[A\x1b[G\x1b[K")
+	outMu.Lock()
+	defer outMu.Unlock()
+	if !IsTerminal(outFile.Fd()) {
+		// the input was echoed to another file (see SetEchoOutput)
+		return
+	}
+	cursorUp(out, inputRows)
+	out.WriteString("\x1b[G\x1b[J")
+}
+
+// showInvalid prints the prompt with the invalid input b muted
+// and the error again.
+func showInvalid(prompt string, opt *InputOpt, b []byte, err error) {
+	resetPrompt()
+	writeOut(func() {
+		fmt.Fprintln(out, prompt+theme.Muted.Render(echoed(string(b), opt.Echo))+theme.Error.Render(fmt.Sprintf(msg(MsgInvalid), err)))
+	})
 }
 
 func moveCursorUp() {
-	fmt.Print("\x1b[A")
+	outMu.Lock()
+	out.WriteString("\x1b[A")
+	outMu.Unlock()
 }
 
 // YesNo gets the answer to a yes/no question. The options string must
@@ -99,15 +366,26 @@ func moveCursorUp() {
 // the second for no (returning false). If one character is upper case,
 // it is the default. The options will be appended to the prompt.
 //   term.YesNo("Exit?", "yN") -> Exit? [yN]
-// It panics if stdin and stdout are not connected to a terminal, if there
-// are more than two characters in options or if both are upper case.
+// It panics if stdin and stdout are not connected to a terminal (unless
+// in non-interactive mode), if there are more than two characters in options
+// or if both are upper case.
 func YesNo(prompt, options string) (bool, error) {
-	checkIsTerminal()
-	if len(options) != 2 {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	return yesNo(prompt, options, &InputOpt{})
+}
+
+// yesNo is YesNo; Key, Timeout and OnTimeout are taken from opt.
+func yesNo(prompt, options string, opt *InputOpt) (bool, error) {
+	checkCanInput()
+	if utf8.RuneCountInString(options) != 2 {
 		panic("exactly 2 options required")
 	}
+	if opt.Key == "" {
+		opt.Key = promptKey(prompt)
+	}
 	prompt = fmt.Sprintf("%s [%s] ", strings.TrimRight(prompt, " "), options)
-	idx, err := Select(prompt, options)
+	idx, err := selectOpt(prompt, options, opt)
 	if err != nil {
 		return false, err
 	}
@@ -117,33 +395,46 @@ func YesNo(prompt, options string) (bool, error) {
 // Select accepts one character from the options string and returns
 // its index within the options. If one character in options is upper case,
 // it is the default.
-// It panics if stdin and stdout are not connected to a terminal or if more
-// than one character are upper case.
+// It panics if stdin and stdout are not connected to a terminal (unless
+// in non-interactive mode) or if more than one character are upper case.
 func Select(prompt, options string) (uint, error) {
-	checkIsTerminal()
-	opt := &InputOpt{Limit: 1}
-	for i, r := range options {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	return selectOpt(prompt, options, &InputOpt{})
+}
+
+// selectOpt is Select; Key, Timeout and OnTimeout are taken from sel.
+func selectOpt(prompt, options string, sel *InputOpt) (uint, error) {
+	checkCanInput()
+	opt := &InputOpt{Limit: 1, Key: sel.Key, Timeout: sel.Timeout, OnTimeout: sel.OnTimeout}
+	runes := []rune(options)
+	for i, r := range runes {
 		if unicode.IsUpper(r) {
 			if opt.Default != nil {
 				panic("only one default option allowed")
 			}
 			opt.Default = uint(i)
 		}
+		runes[i] = unicode.ToLower(r)
 	}
-	options = strings.ToLower(options)
 	opt.ConvFunc = func(s string) (interface{}, error) {
 		s = strings.ToLower(s)
-		i := strings.Index(options, s)
-		if i < 0 {
-			return 0, errors.New("")
+		for i, r := range runes {
+			if string(r) == s {
+				return uint(i), nil
+			}
 		}
-		return uint(i), nil
+		return 0, errNoOption
 	}
 	var idx uint
-	err := Input(prompt, &idx, opt)
+	err := inputAny(prompt, &idx, opt)
 	return idx, err
 }
 
+// errNoOption is the error of the conversion if the input of Select or
+// Menu is not one of the options (see ConversionError).
+var errNoOption = errors.New("no such option")
+
 const (
 	menuFieldSep = " | "
 	menuOptSep   = ") "
@@ -153,14 +444,19 @@ const (
 // within the options slice. If columns is 0, the number of columns will be computed
 // depending on the screen size and the number of options. If title is not "" it will
 // be printed above the menu.
-// It panics if stdin and stdout are not connected to a terminal.
+// It panics if stdin and stdout are not connected to a terminal (unless
+// in non-interactive mode).
 func Menu(prompt, title string, options []string, columns uint) (uint, error) {
+	promptMu.Lock()
+	defer promptMu.Unlock()
 	return menu(prompt, title, options, columns, &InputOpt{})
 }
 
 // MenuWithDefault does the same as Menu but takes a default value for the index of
 // an option. The value will be ignored if dfltIdx >= len(options).
 func MenuWithDefault(prompt, title string, options []string, columns, dfltIdx uint) (uint, error) {
+	promptMu.Lock()
+	defer promptMu.Unlock()
 	opt := &InputOpt{}
 	if dfltIdx < uint(len(options)) {
 		opt.Default = dfltIdx
@@ -168,9 +464,130 @@ func MenuWithDefault(prompt, title string, options []string, columns, dfltIdx ui
 	return menu(prompt, title, options, columns, opt)
 }
 
+// MenuOpt are the options for MenuWithOpt.
+type MenuOpt struct {
+	Columns uint // the number of columns (0: computed from the screen size)
+	// the number of the default option as shown in the menu, i.e. its
+	// index + 1 (0: no default)
+	Default uint
+	// if not "", the chosen option is remembered under this key and
+	// preselected instead of Default the next time a menu with the same
+	// key and option is shown (see MenuChoices)
+	Remember string
+}
+
+// menuChoices are the remembered choices of menus (key -> option).
+var menuChoices = map[string]string{}
+
+// MenuWithOpt does the same as Menu but takes options. The default option
+// is highlighted (Theme.Primary) and selected if only enter is typed.
+//   idx, err := term.MenuWithOpt("Action: ", "", actions,
+//       &term.MenuOpt{Default: 1, Remember: "action"})
+func MenuWithOpt(prompt, title string, options []string, mopt *MenuOpt) (uint, error) {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	if mopt == nil {
+		mopt = &MenuOpt{}
+	}
+	opt := &InputOpt{}
+	if mopt.Default > 0 && mopt.Default <= uint(len(options)) {
+		opt.Default = mopt.Default - 1
+	}
+	if s, ok := menuChoices[mopt.Remember]; ok && mopt.Remember != "" {
+		for i, o := range options {
+			if o == s {
+				opt.Default = uint(i)
+				break
+			}
+		}
+	}
+	idx, err := menu(prompt, title, options, mopt.Columns, opt)
+	if err == nil && mopt.Remember != "" {
+		menuChoices[mopt.Remember] = options[idx]
+	}
+	return idx, err
+}
+
+// MenuChoices returns a copy of the choices remembered by MenuWithOpt
+// (key -> option), e.g. for saving them in a file.
+func MenuChoices() map[string]string {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	m := make(map[string]string, len(menuChoices))
+	for k, v := range menuChoices {
+		m[k] = v
+	}
+	return m
+}
+
+// SetMenuChoices sets the choices remembered by MenuWithOpt (see
+// MenuChoices); nil removes them.
+func SetMenuChoices(m map[string]string) {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	menuChoices = map[string]string{}
+	for k, v := range m {
+		menuChoices[k] = v
+	}
+}
+
+// menuBuf is used to render a menu, so that it can be written at once.
+var menuBuf bytes.Buffer
+
 func menu(prompt, title string, options []string, columns uint, opt *InputOpt) (uint, error) {
-	checkIsTerminal()
+	checkCanInput()
+	optCnt := len(options)
+	if !isNonInteractive() {
+		// the menu is only shown if the answer is typed
+		width, height := getTermSize()
+		dflt := -1
+		if d, ok := opt.Default.(uint); ok {
+			dflt = int(d)
+		}
+		menuBuf.Reset()
+		renderMenu(&menuBuf, title, options, columns, width, height, dflt)
+		menuBuf.WriteByte('\n')
+		writeOut(func() {
+			out.Flush()
+			out.Write(menuBuf.Bytes())
+		})
+		moveCursorUp()
+	}
+	opt.menu = true
+	opt.ConvFunc = func(s string) (interface{}, error) {
+		i, err := strconv.ParseUint(s, 10, 0)
+		if err != nil {
+			return 0, err
+		}
+		if i == 0 || i > uint64(optCnt) {
+			return 0, errNoOption
+		}
+		return uint(i - 1), nil
+	}
+	var idx uint
+	err := inputAny(prompt, &idx, opt)
+	return idx, err
+}
+
+// RenderMenu writes a menu to w like Menu without prompting, e.g. for
+// help output or logs. If columns is 0, the number of columns is computed
+// depending on the screen size (80x24 if stdout is not connected to
+// a terminal) and the number of options. If there are no options, nothing
+// is written.
+func RenderMenu(w io.Writer, title string, options []string, columns uint) error {
+	if len(options) == 0 {
+		return nil
+	}
 	width, height := getTermSize()
+	var b bytes.Buffer
+	renderMenu(&b, title, options, columns, width, height, -1)
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// renderMenu renders the menu; the option with the index dflt
+// is highlighted (-1: none).
+func renderMenu(b *bytes.Buffer, title string, options []string, columns uint, width, height, dflt int) {
 	optCnt := len(options)
 	rowCnt, colCnt := getRowAndColCounts(optCnt, int(columns), height, title != "")
 	maxIdxWidth := len(strconv.Itoa(optCnt))
@@ -180,38 +597,29 @@ func menu(prompt, title string, options []string, columns uint, opt *InputOpt) (
 	}
 	if title != "" {
 		menuWidth := (maxIdxWidth+len(menuOptSep)+maxOptWidth)*colCnt + len(menuFieldSep)*(colCnt-1)
-		fmt.Println(center(title, menuWidth))
-		fmt.Println(strings.Repeat("=", maxInt(menuWidth, utf8.RuneCountInString(title))))
+		b.WriteString(center(title, menuWidth))
+		b.WriteByte('\n')
+		b.WriteString(strings.Repeat("=", maxInt(menuWidth, StringWidth(title))))
+		b.WriteByte('\n')
 	}
-	fmtStr := fmt.Sprintf("%%%dd) %%-%d.%ds", maxIdxWidth, maxOptWidth, maxOptWidth)
 	for row := 0; row < rowCnt; row++ {
 		for col := 0; col < colCnt; col++ {
 			i := col*rowCnt + row
 			if i >= optCnt {
 				break
 			}
-			fmt.Printf(fmtStr, i+1, options[i])
+			opt := truncate(options[i], maxOptWidth)
+			opt = fmt.Sprintf("%*d) %s%s", maxIdxWidth, i+1, opt, strings.Repeat(" ", maxInt(maxOptWidth-StringWidth(opt), 0)))
+			if i == dflt {
+				opt = theme.Primary.Render(opt)
+			}
+			b.WriteString(opt)
 			if col+1 < colCnt {
-				fmt.Print(menuFieldSep)
+				b.WriteString(menuFieldSep)
 			}
 		}
-		fmt.Println()
-	}
-	fmt.Println()
-	moveCursorUp()
-	opt.ConvFunc = func(s string) (interface{}, error) {
-		i, err := strconv.ParseUint(s, 10, 0)
-		if err != nil {
-			return 0, err
-		}
-		if i == 0 || i > uint64(optCnt) {
-			return 0, errors.New("")
-		}
-		return uint(i - 1), nil
+		b.WriteByte('\n')
 	}
-	var idx uint
-	err := Input(prompt, &idx, opt)
-	return idx, err
 }
 
 func getRowAndColCounts(optCnt, columns, height int, withTitle bool) (int, int) {
@@ -241,8 +649,8 @@ func getRowAndColCounts(optCnt, columns, height int, withTitle bool) (int, int)
 }
 
 func getTermSize() (int, int) {
-	width, height, err := GetSize(os.Stdout.Fd())
-	if err != nil {
+	width, height, err := Size(false)
+	if err != nil || width == 0 || height == 0 {
 		width = 80
 		height = 24
 	}
@@ -252,7 +660,7 @@ func getTermSize() (int, int) {
 func getMaxOptionWidth(options []string) int {
 	var w int
 	for _, s := range options {
-		w = maxInt(w, utf8.RuneCountInString(s))
+		w = maxInt(w, StringWidth(s))
 	}
 	return w
 }
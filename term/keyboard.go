@@ -0,0 +1,185 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"sync/atomic"
+	"unicode/utf8"
+
+	"github.com/andreas19/go-term/term/ansi"
+)
+
+// KeyboardProtocol is the protocol the terminal uses to encode keys.
+type KeyboardProtocol int32
+
+const (
+	KeyboardLegacy          KeyboardProtocol = iota // traditional encoding
+	KeyboardKitty                                   // kitty keyboard protocol
+	KeyboardModifyOtherKeys                         // xterm modifyOtherKeys level 2
+)
+
+// ANSI escape codes: query/push/pop kitty keyboard flags (ESC[?u, ESC[>3u
+//                    with 1: disambiguate keys, 2: report event types; ESC[<u),
+//                    set/reset xterm modifyOtherKeys (ESC[>4;2m, ESC[>4;0m).
+const (
+	kittyKeysQuery = "\x1b[?u"
+	kittyKeysOn    = "\x1b[>3u"
+	kittyKeysOff   = "\x1b[<u"
+	otherKeysOn    = "\x1b[>4;2m"
+	otherKeysOff   = "\x1b[>4;0m"
+)
+
+var keyboardProtocol int32 // KeyboardProtocol
+
+// EnableKeyboardEnhancement enables the kitty keyboard protocol if
+// the terminal supports it and otherwise xterm's modifyOtherKeys (terminals
+// that support neither ignore it). It returns the protocol that was
+// enabled. With it ReadEvent can distinguish keys that are encoded
+// identically in the traditional encoding (e.g. ^I and Tab, Esc and Alt)
+// and with the kitty protocol key releases are reported, too. The input
+// functions disable it while they are running. It must be disabled with
+// DisableKeyboardEnhancement before the program exits; RestoreOnExit
+// disables it if the program is terminated by a signal or a panic.
+// It panics if stdin and stdout are not connected to a terminal.
+func EnableKeyboardEnhancement() (KeyboardProtocol, error) {
+	resp, err := queryDA(kittyKeysQuery)
+	if err != nil {
+		return KeyboardLegacy, err
+	}
+	protocol := KeyboardModifyOtherKeys
+	if _, ok := parseCSI(resp, '?', 'u'); ok {
+		protocol = KeyboardKitty
+	}
+	atomic.StoreInt32(&keyboardProtocol, int32(protocol))
+	writeOut(func() { out.WriteString(keyboardOnSeq()) })
+	return protocol, nil
+}
+
+// DisableKeyboardEnhancement restores the traditional encoding of keys.
+func DisableKeyboardEnhancement() {
+	writeOut(func() { out.WriteString(keyboardOffSeq()) })
+	atomic.StoreInt32(&keyboardProtocol, int32(KeyboardLegacy))
+}
+
+// keyboardOnSeq returns the escape code for enabling the current keyboard
+// protocol.
+func keyboardOnSeq() string {
+	switch KeyboardProtocol(atomic.LoadInt32(&keyboardProtocol)) {
+	case KeyboardKitty:
+		return kittyKeysOn
+	case KeyboardModifyOtherKeys:
+		return otherKeysOn
+	}
+	return ""
+}
+
+// keyboardOffSeq returns the escape code for disabling the current
+// keyboard protocol.
+func keyboardOffSeq() string {
+	switch KeyboardProtocol(atomic.LoadInt32(&keyboardProtocol)) {
+	case KeyboardKitty:
+		return kittyKeysOff
+	case KeyboardModifyOtherKeys:
+		return otherKeysOff
+	}
+	return ""
+}
+
+// Modifiers are the modifier keys held down when a key was typed.
+type Modifiers uint8
+
+const (
+	ModShift Modifiers = 1 << iota
+	ModAlt
+	ModCtrl
+	ModSuper
+)
+
+// Codes for keys that do not produce characters (in the private use area
+// of Unicode).
+const (
+	KeyUp rune = 0xF700 + iota
+	KeyDown
+	KeyRight
+	KeyLeft
+	KeyHome
+	KeyEnd
+	KeyInsert
+	KeyDelete
+	KeyPageUp
+	KeyPageDown
+	KeyF1
+	KeyF2
+	KeyF3
+	KeyF4
+	KeyF5
+	KeyF6
+	KeyF7
+	KeyF8
+	KeyF9
+	KeyF10
+	KeyF11
+	KeyF12
+)
+
+// letterKeys maps the final bytes of escape sequences to keys.
+var letterKeys = map[byte]rune{
+	'A': KeyUp, 'B': KeyDown, 'C': KeyRight, 'D': KeyLeft, 'H': KeyHome, 'F': KeyEnd,
+	'P': KeyF1, 'Q': KeyF2, 'R': KeyF3, 'S': KeyF4,
+}
+
+// tildeKeys maps the numbers of ESC[<n>~ sequences to keys.
+var tildeKeys = map[int]rune{
+	1: KeyHome, 2: KeyInsert, 3: KeyDelete, 4: KeyEnd, 5: KeyPageUp, 6: KeyPageDown,
+	7: KeyHome, 8: KeyEnd, 11: KeyF1, 12: KeyF2, 13: KeyF3, 14: KeyF4, 15: KeyF5,
+	17: KeyF6, 18: KeyF7, 19: KeyF8, 20: KeyF9, 21: KeyF10, 23: KeyF11, 24: KeyF12,
+}
+
+// decodeKey sets the fields Code, Mod, Repeat and Release of the event
+// for its key. Code is 0 for unknown escape sequences.
+func (ev *Event) decodeKey() {
+	key := ev.Key
+	switch {
+	case len(key) == 0:
+		return
+	case key[0] != escape || len(key) == 1:
+		ev.Code, _ = utf8.DecodeRune(key)
+		return
+	case len(key) == 3 && key[1] == 'O':
+		// SS3
+		ev.Code = letterKeys[key[2]]
+		return
+	case key[1] != '[':
+		// Alt+key
+		ev.Code, _ = utf8.DecodeRune(key[1:])
+		ev.Mod = ModAlt
+		return
+	}
+	var p ansi.Parser
+	seqs := p.Feed(key)
+	if len(seqs) != 1 || seqs[0].Type != ansi.CSI || seqs[0].Prefix != 0 {
+		return
+	}
+	seq := seqs[0]
+	switch {
+	case seq.Final == 'u':
+		// kitty: ESC[<code>;<modifiers>:<event type>u
+		ev.Code = rune(seq.Param(0, 0))
+	case seq.Final == '~' && seq.Param(0, 0) == 27 && len(seq.Params) >= 3:
+		// modifyOtherKeys: ESC[27;<modifiers>;<code>~
+		ev.Code = rune(seq.Param(2, 0))
+	case seq.Final == '~':
+		ev.Code = tildeKeys[seq.Param(0, 0)]
+	default:
+		ev.Code = letterKeys[seq.Final]
+	}
+	if m := seq.Param(1, 1); m > 1 {
+		ev.Mod = Modifiers(m-1) & (ModShift | ModAlt | ModCtrl | ModSuper)
+	}
+	switch seq.SubParam(1, 1, 1) {
+	case 2:
+		ev.Repeat = true
+	case 3:
+		ev.Release = true
+	}
+}
@@ -0,0 +1,53 @@
+// +build darwin,keyring
+
+package term
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"strings"
+	"unicode"
+)
+
+// Keychain is a CredentialStore that uses the login keychain of macOS with
+// the program security(1). The secrets are stored as generic passwords.
+// It is only built with the build tag keyring.
+type Keychain struct{}
+
+// exit status of security(1) if an item is not found
+const keychainNotFound = 44
+
+// Get returns the secret or ErrCredentialNotFound.
+func (Keychain) Get(service, account string) ([]byte, error) {
+	out, err := exec.Command("security", "find-generic-password", "-s", service, "-a", account, "-w").Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == keychainNotFound {
+		return nil, ErrCredentialNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(out, []byte("\n")), nil
+}
+
+// Set saves the secret. The command is passed to security on stdin,
+// so that the secret is not visible in the list of processes; therefore
+// ErrControlChar is returned if the secret, the service or the account
+// contain control characters (e.g. a newline would end the command).
+func (Keychain) Set(service, account string, secret []byte) error {
+	for _, s := range []string{service, account, string(secret)} {
+		if strings.IndexFunc(s, unicode.IsControl) >= 0 {
+			return ErrControlChar
+		}
+	}
+	cmd := exec.Command("security", "-i")
+	cmd.Stdin = strings.NewReader("add-generic-password -U -s " + keychainQuote(service) +
+		" -a " + keychainQuote(account) + " -w " + keychainQuote(string(secret)) + "\n")
+	return cmd.Run()
+}
+
+// keychainQuote quotes s for the interactive mode of security.
+func keychainQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
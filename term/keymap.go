@@ -0,0 +1,462 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// EditAction is an editing action of the line editor that is used by
+// all input functions. The names in parentheses are used in key map files
+// (see ParseKeyMap); they are the same as the names of the readline functions.
+type EditAction uint8
+
+const (
+	EditNone                 EditAction = iota // no action (removes a binding)
+	EditBeginningOfLine                        // move to the start of the input (beginning-of-line)
+	EditEndOfLine                              // move to the end of the input (end-of-line)
+	EditForwardChar                            // move forward one character (forward-char)
+	EditBackwardChar                           // move back one character (backward-char)
+	EditForwardWord                            // move forward to the end of the next word (forward-word)
+	EditBackwardWord                           // move back to the start of the current or previous word (backward-word)
+	EditDeleteChar                             // delete the character at the cursor (delete-char)
+	EditBackwardDeleteChar                     // delete the character before the cursor (backward-delete-char)
+	EditKillLine                               // kill the text from the cursor to the end (kill-line)
+	EditUnixLineDiscard                        // kill the text before the cursor (unix-line-discard)
+	EditKillWord                               // kill to the end of the next word (kill-word)
+	EditBackwardKillWord                       // kill the word before the cursor (backward-kill-word)
+	EditUnixWordRubout                         // kill the word before the cursor using white space as word boundary (unix-word-rubout)
+	EditYank                                   // insert the last killed text (yank)
+	EditAcceptLine                             // submit the input like Enter (accept-line)
+	EditPreviousHistory                        // previous line in the history (previous-history)
+	EditNextHistory                            // next line in the history (next-history)
+	EditReverseSearchHistory                   // incremental search back in the history (reverse-search-history)
+	EditDumpFunctions                          // show the key bindings; typing searches them (dump-functions)
+)
+
+var editActionNames = map[string]EditAction{
+	"beginning-of-line":      EditBeginningOfLine,
+	"end-of-line":            EditEndOfLine,
+	"forward-char":           EditForwardChar,
+	"backward-char":          EditBackwardChar,
+	"forward-word":           EditForwardWord,
+	"backward-word":          EditBackwardWord,
+	"delete-char":            EditDeleteChar,
+	"backward-delete-char":   EditBackwardDeleteChar,
+	"kill-line":              EditKillLine,
+	"unix-line-discard":      EditUnixLineDiscard,
+	"kill-word":              EditKillWord,
+	"backward-kill-word":     EditBackwardKillWord,
+	"unix-word-rubout":       EditUnixWordRubout,
+	"yank":                   EditYank,
+	"accept-line":            EditAcceptLine,
+	"previous-history":       EditPreviousHistory,
+	"next-history":           EditNextHistory,
+	"reverse-search-history": EditReverseSearchHistory,
+	"dump-functions":         EditDumpFunctions,
+}
+
+// KeyMap maps keys to editing actions. A key is one rune, ESC followed by
+// one rune (Alt/Meta+key) or one escape sequence as sent by the terminal
+// (e.g. "\x1b[H" for Home); sequences of several keys are not supported.
+//
+// The keys for the special characters of the terminal (see stty(1)) that
+// are not bound in the key map work as usual: ERASE (backward-delete-char),
+// KILL (unix-line-discard), WERASE (unix-word-rubout), EOF, INTR, QUIT and SUSP.
+type KeyMap map[string]EditAction
+
+// DefaultKeyMap returns a new key map with emacs-style bindings:
+//   ^A, Home             beginning-of-line
+//   ^E, End              end-of-line
+//   ^F, Right            forward-char
+//   ^B, Left             backward-char
+//   Alt-F, Ctrl-Right    forward-word
+//   Alt-B, Ctrl-Left     backward-word
+//   Delete               delete-char
+//   ^H                   backward-delete-char
+//   ^K                   kill-line
+//   Alt-D                kill-word
+//   Alt-Backspace        backward-kill-word
+//   ^Y                   yank
+//   ^P, Up               previous-history
+//   ^N, Down             next-history
+//   ^R                   reverse-search-history
+//   F1                   dump-functions
+// In menus, which only accept numbers, and in vi normal mode "?" shows
+// the key bindings, too. In other inputs "?" must be typeable, so it is
+// not bound, but it can be if it is not needed:
+//   m := term.DefaultKeyMap()
+//   m.Bind("?", term.EditDumpFunctions)
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		"\x01":      EditBeginningOfLine,
+		"\x1b[H":    EditBeginningOfLine,
+		"\x1bOH":    EditBeginningOfLine,
+		"\x1b[1~":   EditBeginningOfLine,
+		"\x1b[7~":   EditBeginningOfLine,
+		"\x05":      EditEndOfLine,
+		"\x1b[F":    EditEndOfLine,
+		"\x1bOF":    EditEndOfLine,
+		"\x1b[4~":   EditEndOfLine,
+		"\x1b[8~":   EditEndOfLine,
+		"\x06":      EditForwardChar,
+		"\x1b[C":    EditForwardChar,
+		"\x1bOC":    EditForwardChar,
+		"\x02":      EditBackwardChar,
+		"\x1b[D":    EditBackwardChar,
+		"\x1bOD":    EditBackwardChar,
+		"\x1bf":     EditForwardWord,
+		"\x1b[1;5C": EditForwardWord,
+		"\x1b[1;3C": EditForwardWord,
+		"\x1bb":     EditBackwardWord,
+		"\x1b[1;5D": EditBackwardWord,
+		"\x1b[1;3D": EditBackwardWord,
+		"\x1b[3~":   EditDeleteChar,
+		"\x08":      EditBackwardDeleteChar,
+		"\x0b":      EditKillLine,
+		"\x1bd":     EditKillWord,
+		"\x1b\x7f":  EditBackwardKillWord,
+		"\x1b\x08":  EditBackwardKillWord,
+		"\x19":      EditYank,
+		"\x10":      EditPreviousHistory,
+		"\x1b[A":    EditPreviousHistory,
+		"\x1bOA":    EditPreviousHistory,
+		"\x0e":      EditNextHistory,
+		"\x1b[B":    EditNextHistory,
+		"\x1bOB":    EditNextHistory,
+		"\x12":      EditReverseSearchHistory,
+		"\x1bOP":    EditDumpFunctions,
+		"\x1b[11~":  EditDumpFunctions,
+	}
+}
+
+// Bind binds the key to the action; EditNone removes the binding.
+func (m KeyMap) Bind(key string, action EditAction) {
+	if action == EditNone {
+		delete(m, key)
+	} else {
+		m[key] = action
+	}
+}
+
+var keyMap = DefaultKeyMap()
+
+// SetKeyMap sets the key map for all input functions (nil sets the default
+// key map). The key map must not be changed while an input function is running.
+// If ^S or ^Q are bound, software flow control is disabled while an input
+// function is running (unless InputOpt.IXON is TermOn).
+func SetKeyMap(m KeyMap) {
+	if m == nil {
+		m = DefaultKeyMap()
+	}
+	keyMap = m
+}
+
+// ParseKeyMap reads key bindings in the format of readline's inputrc file
+// and returns the default key map with the bindings applied. Each line
+// binds a key to an action:
+//   "\C-a": end-of-line
+//   "\e[H": beginning-of-line
+//   Meta-Rubout: backward-kill-word
+// Key sequences in double quotes may contain the escapes \C- (control),
+// \M- (meta), \e, \\, \", \', \a, \b, \d, \f, \n, \r, \t, \v, \nnn (octal)
+// and \xHH. Key names may have the prefixes Control-, C-, Meta- and M-
+// and may be one of DEL, ESC, ESCAPE, LFD, NEWLINE, RET, RETURN, RUBOUT,
+// SPACE, SPC and TAB or a single character.
+// Empty lines, comments, conditional constructs ($if...), variable settings
+// (set ...), macros and unknown function names are ignored.
+func ParseKeyMap(r io.Reader) (KeyMap, error) {
+	m := DefaultKeyMap()
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == '$' || strings.HasPrefix(line, "set ") {
+			continue
+		}
+		key, rest, err := parseKeySeq(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		rest = strings.TrimSpace(rest)
+		if !strings.HasPrefix(rest, ":") {
+			return nil, fmt.Errorf("line %d: missing ':'", n)
+		}
+		if action, ok := editActionNames[strings.TrimSpace(rest[1:])]; ok {
+			m.Bind(key, action)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// LoadKeyMap reads the key map from the named file (see ParseKeyMap).
+// If name is "", the file named by the environment variable INPUTRC or
+// ~/.inputrc is used; if this file does not exist, the default key map
+// is returned.
+//   if m, err := term.LoadKeyMap(""); err == nil {
+//       term.SetKeyMap(m)
+//   }
+func LoadKeyMap(name string) (KeyMap, error) {
+	optional := name == ""
+	if optional {
+		var err error
+		if name, err = inputrcName(); err != nil {
+			return DefaultKeyMap(), nil
+		}
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		if optional && errors.Is(err, os.ErrNotExist) {
+			return DefaultKeyMap(), nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	m, err := ParseKeyMap(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return m, nil
+}
+
+// inputrcName returns the name of the user's inputrc file.
+func inputrcName() (string, error) {
+	if name := os.Getenv("INPUTRC"); name != "" {
+		return name, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".inputrc"), nil
+}
+
+var keyNames = map[string]byte{
+	"DEL":     0x7F,
+	"ESC":     escape,
+	"ESCAPE":  escape,
+	"LFD":     linefeed,
+	"NEWLINE": linefeed,
+	// CR is translated to LF during input (ICRNL)
+	"RET":    linefeed,
+	"RETURN": linefeed,
+	"RUBOUT": 0x7F,
+	"SPACE":  ' ',
+	"SPC":    ' ',
+	"TAB":    '\t',
+}
+
+// parseKeySeq parses the key sequence at the start of s (in double quotes
+// or a key name) and returns it and the rest of s.
+func parseKeySeq(s string) (string, string, error) {
+	if s[0] != '"' {
+		i := strings.IndexByte(s[1:], ':') + 1
+		if i == 0 {
+			return "", "", errors.New("missing ':'")
+		}
+		key, err := parseKeyName(strings.TrimSpace(s[:i]))
+		return key, s[i:], err
+	}
+	var b []byte
+	for i := 1; i < len(s); {
+		switch s[i] {
+		case '"':
+			return string(b), s[i+1:], nil
+		case '\\':
+			key, n, err := parseKeyEscape(s[i:])
+			if err != nil {
+				return "", "", err
+			}
+			b = append(b, key...)
+			i += n
+		default:
+			b = append(b, s[i])
+			i++
+		}
+	}
+	return "", "", errors.New("missing '\"'")
+}
+
+// parseKeyEscape parses one character or escape sequence at the start of s
+// and returns the resulting bytes and the number of bytes parsed.
+func parseKeyEscape(s string) ([]byte, int, error) {
+	if s == "" {
+		return nil, 0, errors.New("unexpected end of key sequence")
+	}
+	if s[0] != '\\' || len(s) == 1 {
+		return []byte{s[0]}, 1, nil
+	}
+	if len(s) > 2 && s[2] == '-' && (s[1] == 'C' || s[1] == 'M') {
+		b, n, err := parseKeyEscape(s[3:])
+		if err != nil || len(b) == 0 {
+			return nil, 0, errors.New("invalid key sequence")
+		}
+		if s[1] == 'C' {
+			b[len(b)-1] = ctrlKey(b[len(b)-1])
+		} else {
+			b = append([]byte{escape}, b...)
+		}
+		return b, n + 3, nil
+	}
+	if c, ok := map[byte]byte{'e': escape, '\\': '\\', '"': '"', '\'': '\'',
+		'a': '\a', 'b': '\b', 'd': 0x7F, 'f': '\f', 'n': '\n', 'r': '\r',
+		't': '\t', 'v': '\v'}[s[1]]; ok {
+		return []byte{c}, 2, nil
+	}
+	base, start, max := 8, 1, 3
+	if s[1] == 'x' {
+		base, start, max = 16, 2, 2
+	}
+	end := start
+	for end < len(s) && end-start < max && isDigitIn(s[end], base) {
+		end++
+	}
+	if end == start {
+		return []byte{s[1]}, 2, nil
+	}
+	v, err := strconv.ParseUint(s[start:end], base, 8)
+	if err != nil {
+		return nil, 0, err
+	}
+	return []byte{byte(v)}, end, nil
+}
+
+func isDigitIn(c byte, base int) bool {
+	_, err := strconv.ParseUint(string(c), base, 8)
+	return err == nil
+}
+
+func parseKeyName(s string) (string, error) {
+	ctrl, meta := false, false
+	for {
+		lower := strings.ToLower(s)
+		if strings.HasPrefix(lower, "control-") {
+			ctrl, s = true, s[8:]
+		} else if strings.HasPrefix(lower, "c-") && len(s) > 2 {
+			ctrl, s = true, s[2:]
+		} else if strings.HasPrefix(lower, "meta-") {
+			meta, s = true, s[5:]
+		} else if strings.HasPrefix(lower, "m-") && len(s) > 2 {
+			meta, s = true, s[2:]
+		} else {
+			break
+		}
+	}
+	var b []byte
+	if c, ok := keyNames[strings.ToUpper(s)]; ok {
+		b = []byte{c}
+	} else if utf8.RuneCountInString(s) == 1 {
+		b = []byte(s)
+	} else {
+		return "", fmt.Errorf("invalid key name: %q", s)
+	}
+	if ctrl {
+		b[0] = ctrlKey(b[0])
+	}
+	if meta {
+		b = append([]byte{escape}, b...)
+	}
+	return string(b), nil
+}
+
+// ctrlKey returns the control character for c (^? is DEL).
+func ctrlKey(c byte) byte {
+	if c == '?' {
+		return 0x7F
+	}
+	return c & 0x1F
+}
+
+// killBuf contains the text that was killed last.
+var killBuf []byte
+
+// edit executes the action. It returns true if the input is finished.
+func (r *reader) edit(action EditAction) (bool, error) {
+	b := r.buf
+	switch action {
+	case EditBeginningOfLine:
+		r.moveTo(0)
+	case EditEndOfLine:
+		r.moveTo(len(b))
+	case EditForwardChar:
+		r.moveTo(r.nextPos())
+	case EditBackwardChar:
+		r.moveTo(r.prevPos())
+	case EditForwardWord:
+		r.moveTo(r.pos + wordEnd(b[r.pos:], isWordSep))
+	case EditBackwardWord:
+		r.moveTo(wordStart(b[:r.pos], isWordSep))
+	case EditDeleteChar:
+		r.remove(r.pos, r.nextPos())
+	case EditBackwardDeleteChar:
+		r.remove(r.prevPos(), r.pos)
+	case EditKillLine:
+		r.kill(r.pos, len(b))
+	case EditUnixLineDiscard:
+		r.kill(0, r.pos)
+	case EditKillWord:
+		r.kill(r.pos, r.pos+wordEnd(b[r.pos:], isWordSep))
+	case EditBackwardKillWord:
+		r.kill(wordStart(b[:r.pos], isWordSep), r.pos)
+	case EditUnixWordRubout:
+		isSep := unicode.IsSpace
+		if r.opt.PunctWordBreak {
+			isSep = func(ch rune) bool {
+				return unicode.IsSpace(ch) || unicode.IsPunct(ch) || unicode.IsSymbol(ch)
+			}
+		}
+		r.kill(wordStart(b[:r.pos], isSep), r.pos)
+	case EditYank:
+		return r.insert(killBuf)
+	case EditAcceptLine:
+		r.end = EndEnter
+		return true, nil
+	case EditPreviousHistory:
+		r.historyMove(-1)
+	case EditNextHistory:
+		r.historyMove(1)
+	case EditReverseSearchHistory:
+		r.startSearch()
+	case EditDumpFunctions:
+		r.showBindings()
+	}
+	return false, nil
+}
+
+// isWordSep is the definition of a word separator used by readline:
+// all characters except letters and digits.
+func isWordSep(ch rune) bool {
+	return !unicode.IsLetter(ch) && !unicode.IsDigit(ch)
+}
+
+// moveTo moves the cursor to the position pos in the input.
+func (r *reader) moveTo(pos int) {
+	if r.bidi() != nil {
+		r.pos = pos
+		r.render()
+		return
+	}
+	r.MoveTo(pos)
+}
+
+// kill is like remove but saves the removed text for yank
+// (only if the input is echoed).
+func (r *reader) kill(from, to int) {
+	if from < to {
+		if r.opt.Echo == EchoNormal {
+			killBuf = append([]byte{}, r.buf[from:to]...)
+		}
+		r.remove(from, to)
+	}
+}
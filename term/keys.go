@@ -0,0 +1,248 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"bytes"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/sys/unix"
+)
+
+const escape = 0x1B
+
+// escTimeout is the time to wait for the rest of an escape sequence
+// after an ESC character was read.
+const escTimeout = 50 * time.Millisecond
+
+// runeTimeout is the time to wait for the rest of a multi-byte rune.
+const runeTimeout = 500 * time.Millisecond
+
+// ANSI escape codes: enable/disable bracketed paste mode (ESC[?2004h/l),
+//                    start/end of pasted text (ESC[200~ and ESC[201~).
+const (
+	bracketedPasteOn  = "\x1b[?2004h"
+	bracketedPasteOff = "\x1b[?2004l"
+	pasteStart        = "\x1b[200~"
+	pasteEnd          = "\x1b[201~"
+)
+
+// keyReader reads keys (runes or escape sequences) from stdin
+// (or the controlling terminal, see UseTTY).
+type keyReader struct {
+	pending []byte
+	// if not zero, reading fails with ErrTimeout after the deadline
+	deadline time.Time
+	// if true, waiting for a key fails with errResized if the size of
+	// the window changed
+	resize bool
+}
+
+// typeahead are the bytes that were read but not used by the last
+// keyReader (e.g. the next line if several lines were pasted or typed
+// ahead); promptMu must be locked when it is used.
+var typeahead []byte
+
+// useTypeahead starts r with the bytes left over by the last keyReader and
+// returns a function that keeps the bytes r did not use for the next one.
+func (r *keyReader) useTypeahead() func() {
+	r.pending, typeahead = append(typeahead[:0:0], typeahead...), nil
+	return func() {
+		typeahead = append(typeahead[:0:0], r.pending...)
+		r.pending = r.pending[:0]
+	}
+}
+
+// more reads more bytes into r.pending. If timeout >= 0, it waits at most
+// for the timeout and returns false if no bytes were available.
+func (r *keyReader) more(timeout time.Duration) (bool, error) {
+	return r.wait(timeout, false)
+}
+
+// wait is like more; if resize is true, it returns errResized if the size
+// of the window changed (see EnableResizeEvents) before bytes were read.
+func (r *keyReader) wait(timeout time.Duration, resize bool) (bool, error) {
+	wait := timeout < 0 && !r.deadline.IsZero()
+	if wait {
+		if timeout = time.Until(r.deadline); timeout < 0 {
+			timeout = 0
+		}
+	}
+	inject := atomic.LoadInt32(&injecting) != 0
+	if timeout >= 0 || resize || inject {
+		fds := []unix.PollFd{{Fd: int32(inFile.Fd()), Events: unix.POLLIN}}
+		if resize {
+			fds = append(fds, unix.PollFd{Fd: int32(resizePipe[0]), Events: unix.POLLIN})
+		}
+		if inject {
+			fds = append(fds, unix.PollFd{Fd: int32(injectPipe[0]), Events: unix.POLLIN})
+		}
+		ms := -1
+		if timeout >= 0 {
+			ms = int(timeout / time.Millisecond)
+		}
+		n, err := unix.Poll(fds, ms)
+		if err == unix.EINTR {
+			return false, nil
+		}
+		if resize && fds[1].Revents&unix.POLLIN != 0 {
+			if drainResize() {
+				return false, errResized
+			}
+			if fds[0].Revents == 0 {
+				return false, nil
+			}
+		}
+		if inject && fds[len(fds)-1].Revents&unix.POLLIN != 0 {
+			// injected keys (see InjectKeys)
+			if r.readInjected() {
+				return true, nil
+			}
+			if fds[0].Revents == 0 {
+				return false, nil
+			}
+		}
+		if err == nil && n == 0 && wait {
+			err = ErrTimeout
+		}
+		if err != nil || n == 0 {
+			return false, err
+		}
+	}
+	b := make([]byte, 64)
+	cnt, err := inFile.Read(b)
+	if recordInput != nil && cnt > 0 {
+		recordInput.record(b[:cnt])
+	}
+	r.pending = append(r.pending, b[:cnt]...)
+	return cnt > 0, err
+}
+
+// next removes n bytes from r.pending and returns them.
+func (r *keyReader) next(n int) []byte {
+	key := make([]byte, n)
+	copy(key, r.pending)
+	r.pending = r.pending[n:]
+	return key
+}
+
+// readKey returns the next key, which is either one rune or an escape sequence.
+// A single ESC is returned if no other byte follows within escTimeout.
+// Pasted text is returned as one key including the start and end sequences.
+func (r *keyReader) readKey() ([]byte, error) {
+	for len(r.pending) == 0 {
+		if err := r.waitKey(); err != nil {
+			return nil, err
+		}
+	}
+	if r.pending[0] != escape {
+		// a rune may be split across several reads
+		for !utf8.FullRune(r.pending) {
+			if ok, err := r.more(runeTimeout); !ok || err != nil {
+				break
+			}
+		}
+		ch, n := utf8.DecodeRune(r.pending)
+		if ch == utf8.RuneError && n == 1 {
+			// invalid or incomplete UTF-8 sequence
+			r.next(1)
+			return r.readKey()
+		}
+		return r.next(n), nil
+	}
+	if len(r.pending) == 1 {
+		if ok, err := r.more(escTimeout); !ok {
+			return r.next(1), err
+		}
+	}
+	switch r.pending[1] {
+	case '[':
+		// CSI: ESC [ parameter and intermediate bytes, final byte 0x40-0x7E
+		for i := 2; ; i++ {
+			for i >= len(r.pending) {
+				if ok, err := r.more(escTimeout); !ok {
+					return r.next(len(r.pending)), err
+				}
+			}
+			if c := r.pending[i]; c >= 0x40 && c <= 0x7E {
+				if string(r.pending[:i+1]) == pasteStart {
+					return r.readPaste()
+				}
+				return r.next(i + 1), nil
+			}
+		}
+	case 'O':
+		// SS3: ESC O final byte
+		if len(r.pending) < 3 {
+			if ok, err := r.more(escTimeout); !ok {
+				return r.next(len(r.pending)), err
+			}
+		}
+		return r.next(3), nil
+	}
+	// ESC followed by a rune (Alt+key)
+	_, n := utf8.DecodeRune(r.pending[1:])
+	return r.next(1 + n), nil
+}
+
+var (
+	idleTimeout time.Duration
+	idleFunc    func(idle bool)
+)
+
+// SetIdleFunc sets a function that is called with true when no key was
+// typed for the duration d while an input function or ReadEvent is waiting
+// for input and with false when the next key is typed (nil removes it).
+// The key that ends the idle state is discarded, so that the program
+// can lock itself or dim the screen and resume on any key, e.g. in kiosk
+// applications. The function is called from the goroutine of the input
+// function; it may write to stdout, but it must not call an input function.
+//   term.SetIdleFunc(5*time.Minute, func(idle bool) {
+//       if idle {
+//           fmt.Print("\x1b[2m") // dim
+//       } else {
+//           fmt.Print("\x1b[22m")
+//       }
+//   })
+func SetIdleFunc(d time.Duration, f func(idle bool)) {
+	idleTimeout, idleFunc = d, f
+}
+
+// waitKey waits until bytes are available. If no bytes are read within
+// the idle timeout, the idle function is called and the next bytes
+// are discarded.
+func (r *keyReader) waitKey() error {
+	d, f := idleTimeout, idleFunc
+	if f == nil || d <= 0 || !r.deadline.IsZero() && time.Until(r.deadline) < d {
+		_, err := r.wait(-1, r.resize)
+		return err
+	}
+	start := time.Now()
+	for t := d; t > 0; t = d - time.Since(start) {
+		if ok, err := r.wait(t, r.resize); ok || err != nil {
+			return err
+		}
+	}
+	f(true)
+	defer f(false)
+	for len(r.pending) == 0 {
+		if _, err := r.more(-1); err != nil {
+			return err
+		}
+	}
+	r.pending = r.pending[:0]
+	return nil
+}
+
+func (r *keyReader) readPaste() ([]byte, error) {
+	for {
+		if i := bytes.Index(r.pending, []byte(pasteEnd)); i >= 0 {
+			return r.next(i + len(pasteEnd)), nil
+		}
+		if _, err := r.more(-1); err != nil {
+			return nil, err
+		}
+	}
+}
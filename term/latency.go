@@ -0,0 +1,100 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"time"
+)
+
+// ANSI escape code: Device Status Report (DSR) ESC[5n;
+// the terminal responds with ESC[0n.
+const statusQuery = "\x1b[5n"
+
+// Latency returns the time the terminal needs to respond to a status
+// query, which is the round-trip time of the connection if the program
+// runs remotely (e.g. via SSH). It returns ErrNoResponse if the terminal
+// does not respond within the query timeout (see SetQueryTimeout).
+// It panics if stdin and stdout are not connected to a terminal.
+func Latency() (time.Duration, error) {
+	start := time.Now()
+	_, err := query(statusQuery, func(resp []byte) bool {
+		_, ok := parseCSI(resp, 0, 'n')
+		return ok
+	})
+	return time.Since(start), err
+}
+
+// RenderProfile selects how often components that update themselves
+// (e.g. GetOTP and LogView) redraw the screen; changes caused by keys are
+// always drawn at once.
+type RenderProfile uint8
+
+const (
+	RenderFull    RenderProfile = iota // all updates are drawn (default)
+	RenderReduced                      // updates are drawn at most twice per second
+	RenderMinimal                      // updates are drawn at most every 2 seconds
+)
+
+// interval returns the min. time between two updates.
+func (p RenderProfile) interval() time.Duration {
+	switch p {
+	case RenderReduced:
+		return 500 * time.Millisecond
+	case RenderMinimal:
+		return 2 * time.Second
+	}
+	return 0
+}
+
+var renderProfile = RenderFull
+
+// SetRenderProfile sets the render profile of all components.
+func SetRenderProfile(p RenderProfile) {
+	renderProfile = p
+}
+
+// CurrentRenderProfile returns the render profile set with
+// SetRenderProfile or DetectRenderProfile.
+func CurrentRenderProfile() RenderProfile {
+	return renderProfile
+}
+
+// Latency thresholds of DetectRenderProfile.
+const (
+	reducedLatency = 30 * time.Millisecond
+	minimalLatency = 150 * time.Millisecond
+)
+
+// DetectRenderProfile measures the latency of the terminal (the best of
+// three queries, see Latency) and sets the render profile: RenderFull for
+// a local terminal or a fast connection, RenderReduced for more than 30ms
+// and RenderMinimal for more than 150ms or if the terminal does not
+// respond. It returns the profile.
+//   if p, _ := term.DetectRenderProfile(); p != term.RenderFull {
+//       log.Print("slow connection")
+//   }
+// It panics if stdin and stdout are not connected to a terminal.
+func DetectRenderProfile() (RenderProfile, error) {
+	best := time.Duration(-1)
+	for i := 0; i < 3; i++ {
+		d, err := Latency()
+		if err == ErrNoResponse {
+			best = minimalLatency + 1
+			break
+		} else if err != nil {
+			return renderProfile, err
+		}
+		if best < 0 || d < best {
+			best = d
+		}
+	}
+	switch {
+	case best > minimalLatency:
+		renderProfile = RenderMinimal
+	case best > reducedLatency:
+		renderProfile = RenderReduced
+	default:
+		renderProfile = RenderFull
+	}
+	return renderProfile, nil
+}
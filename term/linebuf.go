@@ -0,0 +1,245 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// LineBuffer is a line of input that is edited and echoed the same way as
+// the input of the input functions of this package, so that custom editors
+// render their input consistently with them. The methods write the escape
+// sequences that update the screen to the writer; the cursor of
+// the terminal must be at the position of the cursor in the buffer when
+// a method is called (e.g. after Render).
+//   lb := term.NewLineBuffer(os.Stdout, term.EchoNormal)
+//   lb.Insert([]byte("hello"))
+//   lb.DeleteBack()
+//   lb.MoveTo(0)
+type LineBuffer struct {
+	w        io.Writer
+	mode     EchoMode
+	buf      []byte
+	pos      int // cursor position in buf
+	tabCol   int // column of the start of the input (for tab stops)
+	tabWidth int // distance of the tab stops (0: 8)
+}
+
+// NewLineBuffer returns an empty LineBuffer that writes to w (usually
+// a writer for os.Stdout that is flushed after each update).
+func NewLineBuffer(w io.Writer, echo EchoMode) *LineBuffer {
+	return &LineBuffer{w: w, mode: echo, buf: []byte{}}
+}
+
+// Bytes returns the content of the buffer. It is only valid until
+// the buffer is changed.
+func (lb *LineBuffer) Bytes() []byte {
+	return lb.buf
+}
+
+// Pos returns the position of the cursor (a byte index in Bytes).
+func (lb *LineBuffer) Pos() int {
+	return lb.pos
+}
+
+// Width returns the number of columns the echoed content occupies.
+func (lb *LineBuffer) Width() int {
+	return lb.span(0, len(lb.buf))
+}
+
+// SetTabStops sets the column of the terminal where the input starts
+// (usually the width of the prompt) and the distance of the tab stops
+// (0: 8), so that tabs in the input are echoed as spaces up to the next
+// tab stop.
+func (lb *LineBuffer) SetTabStops(col, width int) {
+	lb.tabCol, lb.tabWidth = col, width
+}
+
+// Render writes the content, erases the rest of the line and moves
+// the cursor to its position; the cursor of the terminal must be
+// at the start of the input.
+// ANSI escape codes: Erase in Line (EL: ESC[K).
+func (lb *LineBuffer) Render() {
+	lb.echoRange(0, len(lb.buf))
+	io.WriteString(lb.w, "\x1b[K")
+	cursorBack(lb.w, lb.span(lb.pos, len(lb.buf)))
+}
+
+// Insert inserts b at the cursor and moves the cursor after it. b must
+// not contain control characters except tabs (see SetTabStops).
+func (lb *LineBuffer) Insert(b []byte) {
+	if lb.pos == len(lb.buf) {
+		lb.buf = append(lb.buf, b...)
+		lb.echoRange(lb.pos, len(lb.buf))
+	} else {
+		lb.buf = append(lb.buf[:lb.pos], append(append([]byte{}, b...), lb.buf[lb.pos:]...)...)
+		// the tail is printed again, the width of tabs may have changed
+		lb.echoRange(lb.pos, len(lb.buf))
+		io.WriteString(lb.w, "\x1b[K")
+		cursorBack(lb.w, lb.span(lb.pos+len(b), len(lb.buf)))
+	}
+	lb.pos += len(b)
+}
+
+// DeleteBack deletes the character (grapheme cluster) before the cursor.
+func (lb *LineBuffer) DeleteBack() {
+	lb.remove(lb.prevPos(), lb.pos)
+}
+
+// DeleteForward deletes the character (grapheme cluster) at the cursor.
+func (lb *LineBuffer) DeleteForward() {
+	lb.remove(lb.pos, lb.nextPos())
+}
+
+// DeleteWord deletes the word before the cursor; words are separated
+// by white space (like ^W).
+func (lb *LineBuffer) DeleteWord() {
+	lb.remove(wordStart(lb.buf[:lb.pos], unicode.IsSpace), lb.pos)
+}
+
+// MoveTo moves the cursor to the position pos (a byte index in Bytes,
+// which must be at the start of a character).
+func (lb *LineBuffer) MoveTo(pos int) {
+	if pos < lb.pos {
+		cursorBack(lb.w, lb.span(pos, lb.pos))
+	} else {
+		cursorForward(lb.w, lb.span(lb.pos, pos))
+	}
+	lb.pos = pos
+}
+
+// remove removes the bytes from:to from the buffer and the screen;
+// the cursor is moved to from.
+// ANSI escape codes: Erase in Line (EL: ESC[K).
+func (lb *LineBuffer) remove(from, to int) {
+	if from >= to {
+		return
+	}
+	lb.MoveTo(from)
+	lb.buf = append(lb.buf[:from], lb.buf[to:]...)
+	lb.echoRange(from, len(lb.buf))
+	io.WriteString(lb.w, "\x1b[K")
+	cursorBack(lb.w, lb.span(from, len(lb.buf)))
+}
+
+// tabAdvance returns the number of columns a tab at the column col of
+// the input advances the cursor.
+func (lb *LineBuffer) tabAdvance(col int) int {
+	width := lb.tabWidth
+	if width <= 0 {
+		width = 8
+	}
+	return width - (lb.tabCol+col)%width
+}
+
+// hasTabs returns whether tabs in buf[:to] are expanded.
+func (lb *LineBuffer) hasTabs(to int) bool {
+	return lb.mode == EchoNormal && bytes.IndexByte(lb.buf[:to], '\t') >= 0
+}
+
+// column returns the column of the position pos relative to the start
+// of the input.
+func (lb *LineBuffer) column(pos int) int {
+	if !lb.hasTabs(pos) {
+		return lb.echoWidth(lb.buf[:pos])
+	}
+	col := 0
+	for b := lb.buf[:pos]; len(b) > 0; {
+		n := graphemeLen(b)
+		if b[0] == '\t' {
+			col += lb.tabAdvance(col)
+		} else {
+			col += clusterWidth(b[:n])
+		}
+		b = b[n:]
+	}
+	return col
+}
+
+// span returns the number of columns buf[from:to] occupies on the screen.
+func (lb *LineBuffer) span(from, to int) int {
+	if !lb.hasTabs(to) {
+		return lb.echoWidth(lb.buf[from:to])
+	}
+	return lb.column(to) - lb.column(from)
+}
+
+// echoRange prints buf[from:to] according to the echo mode; tabs are
+// printed as spaces up to the next tab stop.
+func (lb *LineBuffer) echoRange(from, to int) {
+	if !lb.hasTabs(to) {
+		lb.echo(lb.buf[from:to])
+		return
+	}
+	var s strings.Builder
+	col := lb.column(from)
+	for b := lb.buf[from:to]; len(b) > 0; {
+		n := graphemeLen(b)
+		if b[0] == '\t' {
+			adv := lb.tabAdvance(col)
+			s.WriteString(strings.Repeat(" ", adv))
+			col += adv
+		} else {
+			s.Write(b[:n])
+			col += clusterWidth(b[:n])
+		}
+		b = b[n:]
+	}
+	io.WriteString(lb.w, s.String())
+}
+
+// echo prints b according to the echo mode.
+func (lb *LineBuffer) echo(b []byte) {
+	switch lb.mode {
+	case EchoNormal:
+		lb.w.Write(b)
+	case EchoMask:
+		io.WriteString(lb.w, strings.Repeat(string(maskChar), utf8.RuneCount(b)))
+	}
+}
+
+// echoWidth returns the number of columns b occupies on the screen.
+func (lb *LineBuffer) echoWidth(b []byte) int {
+	switch lb.mode {
+	case EchoNormal:
+		return bytesWidth(b)
+	case EchoMask:
+		return utf8.RuneCount(b)
+	}
+	return 0
+}
+
+// nextPos returns the position after the grapheme cluster at the cursor.
+func (lb *LineBuffer) nextPos() int {
+	return lb.pos + graphemeLen(lb.buf[lb.pos:])
+}
+
+// prevPos returns the position of the grapheme cluster before the cursor.
+func (lb *LineBuffer) prevPos() int {
+	return lb.pos - lastGraphemeLen(lb.buf[:lb.pos])
+}
+
+// ANSI escape codes: Cursor Back (CUB: ESC[nD), Cursor Forward (CUF: ESC[nC).
+func cursorBack(w io.Writer, n int) {
+	if n > 0 {
+		fmt.Fprintf(w, "\x1b[%dD", n)
+	}
+}
+
+func cursorForward(w io.Writer, n int) {
+	if n > 0 {
+		fmt.Fprintf(w, "\x1b[%dC", n)
+	}
+}
+
+// ANSI escape code: Cursor Up (CUU: ESC[nA).
+func cursorUp(w io.Writer, n int) {
+	if n > 0 {
+		fmt.Fprintf(w, "\x1b[%dA", n)
+	}
+}
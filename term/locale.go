@@ -0,0 +1,230 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Locale contains the conventions for formatting and parsing numbers.
+type Locale struct {
+	Decimal  rune // decimal separator
+	Grouping rune // thousands separator (0: no grouping)
+}
+
+// Some common locales.
+var (
+	LocaleC  = Locale{Decimal: '.'}
+	LocaleEN = Locale{Decimal: '.', Grouping: ','}
+	LocaleDE = Locale{Decimal: ',', Grouping: '.'}
+	LocaleFR = Locale{Decimal: ',', Grouping: '\u202f'} // narrow no-break space
+	LocaleCH = Locale{Decimal: '.', Grouping: '\''}
+)
+
+var locale = LocaleC
+
+// SetLocale sets the locale that is used for formatting numbers by
+// the output functions of this package (e.g. BarChart). The initial
+// locale is LocaleC.
+func SetLocale(l Locale) {
+	if l.Decimal == 0 {
+		l.Decimal = '.'
+	}
+	locale = l
+}
+
+// LocaleFromEnv returns the locale for the language and territory in
+// the first of the environment variables LC_ALL, LC_NUMERIC and LANG that
+// is set (e.g. "de_DE.UTF-8"). Only common languages are known; for
+// others LocaleC is returned.
+func LocaleFromEnv() Locale {
+	var name string
+	for _, v := range []string{"LC_ALL", "LC_NUMERIC", "LANG"} {
+		if name = os.Getenv(v); name != "" {
+			break
+		}
+	}
+	if i := strings.IndexAny(name, ".@"); i >= 0 {
+		name = name[:i]
+	}
+	lang, territory := name, ""
+	if i := strings.IndexByte(name, '_'); i >= 0 {
+		lang, territory = name[:i], name[i+1:]
+	}
+	switch lang {
+	case "en", "ja", "zh", "ko", "he", "th", "ga", "mt":
+		return LocaleEN
+	case "de", "it":
+		if territory == "CH" {
+			return LocaleCH
+		}
+		return LocaleDE
+	case "es", "nl", "pt", "da", "id", "tr", "el", "ro", "hr", "sl", "sr":
+		return LocaleDE
+	case "fr", "ru", "pl", "cs", "sk", "uk", "sv", "fi", "nb", "nn", "no", "hu",
+		"bg", "et", "lv", "lt":
+		return LocaleFR
+	}
+	return LocaleC
+}
+
+// FormatFloat formats f with prec digits after the decimal separator
+// (-1: the smallest number necessary to represent the value exactly)
+// and groups the digits before it.
+func (l Locale) FormatFloat(f float64, prec int) string {
+	return l.localize(strconv.FormatFloat(f, 'f', prec, 64))
+}
+
+// FormatInt formats i with grouped digits.
+func (l Locale) FormatInt(i int64) string {
+	return l.localize(strconv.FormatInt(i, 10))
+}
+
+// localize replaces the decimal point in s and inserts the grouping
+// characters.
+func (l Locale) localize(s string) string {
+	sign := ""
+	if s != "" && s[0] == '-' {
+		sign, s = "-", s[1:]
+	}
+	intPart, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, frac = s[:i], s[i+1:]
+	}
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, c := range intPart {
+		if i > 0 && l.Grouping != 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteRune(l.Grouping)
+		}
+		b.WriteRune(c)
+	}
+	if frac != "" {
+		b.WriteRune(l.decimal())
+		b.WriteString(frac)
+	}
+	return b.String()
+}
+
+func (l Locale) decimal() rune {
+	if l.Decimal == 0 {
+		return '.'
+	}
+	return l.Decimal
+}
+
+// isGrouping returns true if r is the grouping character; if it is
+// a space character, all space characters are accepted.
+func (l Locale) isGrouping(r rune) bool {
+	return l.Grouping != 0 && (r == l.Grouping ||
+		unicode.IsSpace(l.Grouping) && unicode.IsSpace(r))
+}
+
+// normalize converts a localized number to the format used by strconv.
+// Grouping characters must separate groups of three digits.
+func (l Locale) normalize(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	invalid := fmt.Errorf("invalid number: %q", s)
+	var b strings.Builder
+	digits := 0 // digits in the current group of the integer part
+	grouped := false
+	intPart := true
+	for i, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			if intPart {
+				digits++
+			}
+			b.WriteRune(r)
+		case (r == '-' || r == '+') && i == 0:
+			b.WriteRune(r)
+		case intPart && l.isGrouping(r):
+			if digits == 0 || digits > 3 || grouped && digits != 3 {
+				return "", invalid
+			}
+			grouped = true
+			digits = 0
+		case intPart && r == l.decimal():
+			if grouped && digits != 3 {
+				return "", invalid
+			}
+			intPart = false
+			b.WriteByte('.')
+		default:
+			return "", invalid
+		}
+	}
+	if intPart && grouped && digits != 3 {
+		return "", invalid
+	}
+	return b.String(), nil
+}
+
+// ParseFloat parses a localized number.
+//   term.LocaleDE.ParseFloat("1.234,56") -> 1234.56
+func (l Locale) ParseFloat(s string) (float64, error) {
+	n, err := l.normalize(s)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(n, 64)
+}
+
+// ParseInt parses a localized integer.
+func (l Locale) ParseInt(s string) (int64, error) {
+	n, err := l.normalize(s)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(n, 10, 64)
+}
+
+// converter returns a function that converts localized input for *in
+// or nil if the type of *in is not supported.
+func (l Locale) converter(in interface{}) func(string) error {
+	switch p := in.(type) {
+	case *int:
+		return func(s string) error {
+			i, err := l.ParseInt(s)
+			if err == nil && int64(int(i)) != i {
+				err = strconv.ErrRange
+			}
+			if err == nil {
+				*p = int(i)
+			}
+			return err
+		}
+	case *int64:
+		return func(s string) error {
+			i, err := l.ParseInt(s)
+			if err == nil {
+				*p = i
+			}
+			return err
+		}
+	case *uint:
+		return func(s string) error {
+			i, err := l.ParseInt(s)
+			if err == nil && (i < 0 || uint64(uint(i)) != uint64(i)) {
+				err = strconv.ErrRange
+			}
+			if err == nil {
+				*p = uint(i)
+			}
+			return err
+		}
+	case *float64:
+		return func(s string) error {
+			f, err := l.ParseFloat(s)
+			if err == nil {
+				*p = f
+			}
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogRule styles the lines that match the pattern (see LogViewOpt).
+type LogRule struct {
+	Pattern *regexp.Regexp
+	Style   Style
+}
+
+// LogViewOpt contains the options for the LogView function.
+type LogViewOpt struct {
+	Height   int       // number of lines shown; default: height of the terminal - 1
+	MaxLines int       // number of lines kept for scrolling; default: 10 * Height
+	Rules    []LogRule // the style of the first matching rule is used
+}
+
+// logViewTick is the interval in which LogView shows new lines.
+const logViewTick = 100 * time.Millisecond
+
+// LogView shows the last lines received from the channel (e.g. the output
+// of a running command) with a status line below them. New lines are shown
+// as they arrive (follow mode). The Up and Down keys (PgUp, PgDn, Home)
+// scroll back through the kept lines and stop following; End or f resume it.
+// Lines that are longer than the width of the terminal are cut off. LogView
+// returns when q, Enter or Esc is typed; if SetCancelOnEsc is enabled, Esc
+// returns ErrCanceled. opt may be nil.
+//   err := term.LogView(lines, &term.LogViewOpt{Rules: []term.LogRule{
+//       {Pattern: regexp.MustCompile(`\bERROR\b`), Style: term.Style{Fg: term.Red}},
+//   }})
+// It panics if stdin and stdout are not connected to a terminal.
+func LogView(lines <-chan string, opt *LogViewOpt) error {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	checkIsTerminal()
+	if opt == nil {
+		opt = &LogViewOpt{}
+	}
+	width, height := getTermSize()
+	v := &logView{in: lines, opt: opt, width: width, height: opt.Height, follow: true}
+	if v.height <= 0 {
+		v.height = maxInt(height-1, 1)
+	}
+	v.max = opt.MaxLines
+	if v.max <= 0 {
+		v.max = 10 * v.height
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go v.receive(done)
+	return runWidgetTick(v.lines, v.handle, logViewTick)
+}
+
+// LogViewReader is like LogView, but the lines are read from r (until EOF).
+func LogViewReader(r io.Reader, opt *LogViewOpt) error {
+	ch := make(chan string)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		defer close(ch)
+		sc := bufio.NewScanner(r)
+		for sc.Scan() {
+			select {
+			case ch <- sc.Text():
+			case <-done:
+				return
+			}
+		}
+	}()
+	return LogView(ch, opt)
+}
+
+// logView is the state of LogView.
+type logView struct {
+	mu     sync.Mutex // guards buf, top and closed
+	in     <-chan string
+	opt    *LogViewOpt
+	buf    []string
+	max    int
+	width  int
+	height int
+	follow bool
+	top    int  // index of the first shown line if not following
+	closed bool // the channel is closed
+}
+
+// receive appends the lines from the channel to the buffer until it is
+// closed or done is closed.
+func (v *logView) receive(done <-chan struct{}) {
+	for {
+		select {
+		case line, ok := <-v.in:
+			v.mu.Lock()
+			if !ok {
+				v.closed = true
+				v.mu.Unlock()
+				return
+			}
+			v.buf = append(v.buf, strings.ReplaceAll(line, "\t", "    "))
+			if n := len(v.buf) - v.max; n > 0 {
+				v.buf = v.buf[n:]
+				v.top = maxInt(v.top-n, 0)
+			}
+			v.mu.Unlock()
+		case <-done:
+			return
+		}
+	}
+}
+
+// lastTop returns the index of the first line if the last lines are shown.
+func (v *logView) lastTop() int {
+	return maxInt(len(v.buf)-v.height, 0)
+}
+
+func (v *logView) lines() []string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.follow {
+		v.top = v.lastTop()
+	}
+	lines := make([]string, 0, v.height+1)
+	for i := v.top; i < v.top+v.height; i++ {
+		if i >= len(v.buf) {
+			lines = append(lines, "")
+			continue
+		}
+		line := truncate(stripEscapes(v.buf[i]), v.width)
+		for _, rule := range v.opt.Rules {
+			if rule.Pattern.MatchString(line) {
+				line = rule.Style.Render(line)
+				break
+			}
+		}
+		lines = append(lines, line)
+	}
+	var status string
+	if v.follow {
+		status = fmt.Sprintf(msg(MsgLogFollow), len(v.buf))
+	} else {
+		status = fmt.Sprintf(msg(MsgLogScroll), v.top+1, minInt(v.top+v.height, len(v.buf)), len(v.buf))
+	}
+	if v.closed {
+		status += msg(MsgLogEnd)
+	}
+	return append(lines, theme.Muted.Render(truncate(status, v.width)))
+}
+
+func (v *logView) handle(key string) (bool, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	scroll := func(n int) {
+		if v.follow {
+			v.top = v.lastTop()
+		}
+		v.top = maxInt(minInt(v.top+n, v.lastTop()), 0)
+		v.follow = false
+	}
+	switch key {
+	case "\x1b[A", "\x1bOA":
+		scroll(-1)
+	case "\x1b[B", "\x1bOB":
+		scroll(1)
+	case "\x1b[5~":
+		scroll(-v.height)
+	case "\x1b[6~":
+		scroll(v.height)
+	case "\x1b[H", "\x1bOH", "\x1b[1~", "\x1b[7~":
+		scroll(-len(v.buf))
+	case "\x1b[F", "\x1bOF", "\x1b[4~", "\x1b[8~":
+		v.follow = true
+	case "f":
+		v.follow = !v.follow
+	case "q", "\x1b":
+		if key == "\x1b" && cancelOnEsc {
+			return true, ErrCanceled
+		}
+		return true, nil
+	}
+	return widgetDone(key)
+}
@@ -0,0 +1,134 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// LogWriterOpt contains the options for the NewLogWriter function.
+type LogWriterOpt struct {
+	Out   io.Writer // default: os.Stderr
+	Color bool      // colorize even if Out is not a terminal
+}
+
+var (
+	logTimeRe  = regexp.MustCompile(`^\d{4}[-/]\d\d[-/]\d\d[T ]\d\d:\d\d:\d\d(?:\.\d+)?(?:Z|[+-]\d\d:?\d\d)?|^\d\d:\d\d:\d\d(?:\.\d+)?`)
+	logLevelRe = regexp.MustCompile(`(?i:\blevel=)"?(\w+)"?|\[(\w+)\]|\b(TRACE|DEBUG|INFO|WARN|WARNING|ERROR|FATAL|PANIC)\b`)
+	logAttrRe  = regexp.MustCompile(`(^|\s)([\w.-]+)=("(?:[^"\\]|\\.)*"|\S*)`)
+)
+
+// NewLogWriter returns a writer for log output (e.g. of a log.Logger) that
+// colorizes the lines written to it: timestamps at the start of a line,
+// the level (e.g. "ERROR", "[warn]" or "level=info") with the styles of
+// the theme (see SetTheme) and the keys of key=value attributes. The lines
+// are written unchanged if Out is not a terminal (unless Color is set) or
+// if the environment variable NO_COLOR is not empty. opt may be nil.
+//   log.SetOutput(term.NewLogWriter(nil))
+//   log.Println("ERROR cannot open file:", err)
+// It can also be used as the output of a slog.TextHandler (log/slog is
+// not available with the Go version this module supports, so there is no
+// handler of its own). The writer is safe for concurrent use.
+func NewLogWriter(opt *LogWriterOpt) io.Writer {
+	if opt == nil {
+		opt = &LogWriterOpt{}
+	}
+	w := &logWriter{out: opt.Out, color: opt.Color}
+	if w.out == nil {
+		w.out = os.Stderr
+	}
+	if f, ok := w.out.(*os.File); ok && IsTerminal(f.Fd()) {
+		w.color = true
+	}
+	return w
+}
+
+type logWriter struct {
+	mu    sync.Mutex
+	out   io.Writer
+	color bool
+	buf   []byte // an incomplete line
+}
+
+// Write writes p to the underlying writer; an incomplete line at the end
+// of p is written when it is completed by a later call of Write.
+func (w *logWriter) Write(p []byte) (int, error) {
+	if !w.color || noColor() {
+		return w.out.Write(p)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	i := bytes.LastIndexByte(w.buf, '\n')
+	if i < 0 {
+		return len(p), nil
+	}
+	var b strings.Builder
+	for _, line := range strings.SplitAfter(string(w.buf[:i+1]), "\n") {
+		b.WriteString(colorizeLog(strings.TrimSuffix(line, "\n")))
+		if strings.HasSuffix(line, "\n") {
+			b.WriteString("\n")
+		}
+	}
+	w.buf = append(w.buf[:0], w.buf[i+1:]...)
+	if _, err := io.WriteString(w.out, b.String()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// colorizeLog returns a log line with the styles of the theme applied.
+func colorizeLog(line string) string {
+	var ts string
+	if loc := logTimeRe.FindStringIndex(line); loc != nil {
+		ts, line = theme.Muted.Render(line[:loc[1]]), line[loc[1]:]
+	}
+	m := logLevelRe.FindStringSubmatchIndex(line)
+	for i := 2; m != nil && i < len(m); i += 2 {
+		if m[i] >= 0 {
+			if style, ok := levelStyle(line[m[i]:m[i+1]]); ok {
+				return ts + colorizeAttrs(line[:m[i]]) + style.Render(line[m[i]:m[i+1]]) +
+					colorizeAttrs(line[m[i+1]:])
+			}
+			break
+		}
+	}
+	return ts + colorizeAttrs(line)
+}
+
+// levelStyle returns the style of a log level.
+func levelStyle(level string) (Style, bool) {
+	switch strings.ToUpper(level) {
+	case "TRACE", "DEBUG":
+		return theme.Muted, true
+	case "INFO":
+		return theme.Primary, true
+	case "WARN", "WARNING":
+		return theme.Warning, true
+	case "ERROR", "ERR":
+		return theme.Error, true
+	case "FATAL", "PANIC", "CRIT", "CRITICAL":
+		style := theme.Error
+		style.Bold = true
+		return style, true
+	}
+	return Style{}, false
+}
+
+// colorizeAttrs returns s with the keys of key=value attributes styled;
+// the values of time attributes are muted.
+func colorizeAttrs(s string) string {
+	return logAttrRe.ReplaceAllStringFunc(s, func(attr string) string {
+		m := logAttrRe.FindStringSubmatch(attr)
+		value := m[3]
+		if m[2] == "time" || m[2] == "ts" {
+			value = theme.Muted.Render(value)
+		}
+		return m[1] + theme.Accent.Render(m[2]) + "=" + value
+	})
+}
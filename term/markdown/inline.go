@@ -0,0 +1,151 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package markdown
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/andreas19/go-term/term"
+)
+
+// piece is a part of a paragraph with the same style.
+type piece struct {
+	text  string
+	style term.Style
+	url   string // the target if the piece is part of a link or an image
+}
+
+// escapable are the characters that can be escaped with a backslash.
+const escapable = "\\`*_{}[]()#+-.!|<>~"
+
+var (
+	linkRe     = regexp.MustCompile(`^\[([^\]]*)\]\(\s*<?([^\s>)]*)>?(?:\s+"[^"]*")?\s*\)`)
+	autolinkRe = regexp.MustCompile(`^<((?:https?://|mailto:)[^>\s]+)>`)
+)
+
+// inline returns the words of the text s with inline formatting.
+func (r *renderer) inline(s string, base term.Style) []word {
+	var words []word
+	var text strings.Builder
+	width := 0
+	for _, p := range r.pieces(s, base, "") {
+		parts := strings.Split(p.text, " ")
+		for i, part := range parts {
+			if i > 0 && width > 0 {
+				words = append(words, word{text.String(), width})
+				text.Reset()
+				width = 0
+			}
+			if part == "" {
+				continue
+			}
+			styled := p.style.Render(part)
+			if strings.Contains(p.url, ":") && !r.opt.NoLinks {
+				// only absolute URLs can be opened by the terminal
+				styled = term.Hyperlink(p.url, styled)
+			}
+			text.WriteString(styled)
+			width += term.StringWidth(part)
+		}
+	}
+	if width > 0 {
+		words = append(words, word{text.String(), width})
+	}
+	return words
+}
+
+// pieces splits s into pieces with the same style; url is the target
+// of the link s is part of (if any).
+func (r *renderer) pieces(s string, base term.Style, url string) []piece {
+	var ps []piece
+	var text strings.Builder
+	bold, italic := base.Bold, base.Italic
+	flush := func() {
+		if text.Len() > 0 {
+			style := base
+			style.Bold, style.Italic = bold, italic
+			ps = append(ps, piece{text.String(), style, url})
+			text.Reset()
+		}
+	}
+	for i := 0; i < len(s); {
+		rest := s[i:]
+		c := s[i]
+		switch {
+		case c == '\\' && len(rest) > 1 && strings.IndexByte(escapable, rest[1]) >= 0:
+			text.WriteByte(rest[1])
+			i += 2
+		case c == '`':
+			n := len(rest) - len(strings.TrimLeft(rest, "`"))
+			end := strings.Index(rest[n:], rest[:n])
+			if end < 0 {
+				text.WriteString(rest[:n])
+				i += n
+				break
+			}
+			flush()
+			code := strings.TrimSpace(rest[n : n+end])
+			ps = append(ps, piece{code, r.theme.Accent, url})
+			i += 2*n + end
+		case strings.HasPrefix(rest, "**") || strings.HasPrefix(rest, "__"):
+			if bold != base.Bold || strings.Contains(rest[2:], rest[:2]) {
+				flush()
+				bold = !bold
+			} else {
+				text.WriteString(rest[:2])
+			}
+			i += 2
+		case (c == '*' || c == '_') && r.emphasis(s, i, italic != base.Italic):
+			flush()
+			italic = !italic
+			i++
+		case c == '!' && linkRe.MatchString(rest[1:]):
+			// an image is shown as its alternative text
+			m := linkRe.FindStringSubmatch(rest[1:])
+			flush()
+			ps = append(ps, r.pieces(m[1], r.theme.Muted, m[2])...)
+			i += 1 + len(m[0])
+		case c == '[' && linkRe.MatchString(rest):
+			m := linkRe.FindStringSubmatch(rest)
+			flush()
+			style := base
+			style.Bold, style.Italic = bold, italic
+			style.Fg, style.Underline = r.theme.Accent.Fg, true
+			ps = append(ps, r.pieces(m[1], style, m[2])...)
+			if r.opt.NoLinks && m[2] != "" {
+				ps = append(ps, piece{" (" + m[2] + ")", r.theme.Muted, ""})
+			}
+			i += len(m[0])
+		case c == '<' && autolinkRe.MatchString(rest):
+			m := autolinkRe.FindStringSubmatch(rest)
+			flush()
+			ps = append(ps, piece{m[1], term.Style{Fg: r.theme.Accent.Fg, Underline: true}, m[1]})
+			i += len(m[0])
+		default:
+			_, n := utf8.DecodeRuneInString(rest)
+			text.WriteString(rest[:n])
+			i += n
+		}
+	}
+	flush()
+	return ps
+}
+
+// emphasis reports whether the * or _ at index i in s starts (open is
+// false) or ends emphasis. An underscore within a word does not.
+func (r *renderer) emphasis(s string, i int, open bool) bool {
+	c := s[i]
+	before, _ := utf8.DecodeLastRuneInString(s[:i])
+	after, _ := utf8.DecodeRuneInString(s[i+1:])
+	alnum := func(ch rune) bool { return unicode.IsLetter(ch) || unicode.IsDigit(ch) }
+	if c == '_' && i > 0 && alnum(before) && i+1 < len(s) && alnum(after) {
+		return false
+	}
+	if open {
+		return i > 0 && !unicode.IsSpace(before)
+	}
+	return i+1 < len(s) && !unicode.IsSpace(after) && strings.IndexByte(s[i+1:], c) >= 0
+}
@@ -0,0 +1,297 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+/*
+Package markdown renders a subset of Markdown as styled text for terminals,
+e.g. for showing a README or the help of a program.
+
+Supported are headings (#), paragraphs, emphasis (*em*, **strong**),
+code spans and code blocks (fenced or indented), block quotes, bullet and
+ordered lists (also nested), horizontal rules, links (as hyperlinks, see
+term.Hyperlink), images (as their alternative text) and tables (see
+term.Table). HTML is shown as text. The styles are taken from the theme
+of package term (see term.SetTheme).
+*/
+package markdown
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/andreas19/go-term/term"
+)
+
+// Opt contains the options for the Render function.
+type Opt struct {
+	Width   int  // width of the text; default: width of the terminal
+	NoLinks bool // show links as "text (url)" instead of hyperlinks
+}
+
+var (
+	headingRe = regexp.MustCompile(`^ {0,3}(#{1,6})(?:\s+(.*?))?(?:\s+#+)?\s*$`)
+	ruleRe    = regexp.MustCompile(`^ {0,3}(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,})$`)
+	fenceRe   = regexp.MustCompile("^ {0,3}(```+|~~~+)")
+	itemRe    = regexp.MustCompile(`^(\s*)([-*+]|\d{1,9}[.)])(?:\s+(.*))?$`)
+	tableRe   = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+)
+
+// bullets are the bullets of the levels of nested lists.
+var bullets = []string{"•", "◦", "▪"}
+
+// Render returns the Markdown text src rendered for a terminal. opt may
+// be nil.
+//   fmt.Print(markdown.Render(readme, nil))
+func Render(src string, opt *Opt) string {
+	if opt == nil {
+		opt = &Opt{}
+	}
+	width := opt.Width
+	if width <= 0 {
+		width = 80
+		if w, _, err := term.Size(false); err == nil && w > 0 {
+			width = int(w)
+		}
+	}
+	r := &renderer{opt: opt, theme: term.CurrentTheme()}
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	return r.render(strings.Split(strings.TrimRight(src, "\n"), "\n"), width)
+}
+
+type renderer struct {
+	opt   *Opt
+	theme term.Theme
+}
+
+// render renders the lines of Markdown text for the width.
+func (r *renderer) render(lines []string, width int) string {
+	var b strings.Builder
+	// blank separates blocks with an empty line
+	blank := func() {
+		if b.Len() > 0 && !strings.HasSuffix(b.String(), "\n\n") {
+			b.WriteString("\n")
+		}
+	}
+	inList := false
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			i++
+			inList = false
+			continue
+		}
+		if m := itemRe.FindStringSubmatch(line); m != nil && !ruleRe.MatchString(line) {
+			if !inList {
+				blank()
+			}
+			inList = true
+			i = r.item(&b, lines, i, m, width)
+			continue
+		}
+		inList = false
+		blank()
+		switch {
+		case fenceRe.MatchString(line):
+			i = r.fencedCode(&b, lines, i)
+		case headingRe.MatchString(line):
+			m := headingRe.FindStringSubmatch(line)
+			r.heading(&b, len(m[1]), m[2], width)
+			i++
+		case ruleRe.MatchString(line):
+			b.WriteString(r.theme.Muted.Render(strings.Repeat("─", width)) + "\n")
+			i++
+		case strings.HasPrefix(trimmed, ">"):
+			i = r.quote(&b, lines, i, width)
+		case strings.Contains(line, "|") && i+1 < len(lines) && tableRe.MatchString(lines[i+1]) &&
+			strings.Contains(lines[i+1], "-"):
+			i = r.table(&b, lines, i, width)
+		case strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t"):
+			for ; i < len(lines) && (strings.TrimSpace(lines[i]) == "" || strings.HasPrefix(lines[i], "    ") ||
+				strings.HasPrefix(lines[i], "\t")); i++ {
+				b.WriteString(r.code(strings.TrimPrefix(strings.TrimPrefix(lines[i], "\t"), "    ")))
+			}
+		default:
+			var para []string
+			for ; i < len(lines) && !r.blockStart(lines, i); i++ {
+				para = append(para, strings.TrimSpace(lines[i]))
+			}
+			r.wrap(&b, r.inline(strings.Join(para, " "), term.Style{}), width, "", "")
+		}
+	}
+	return b.String()
+}
+
+// blockStart reports whether the line with index i ends a paragraph.
+func (r *renderer) blockStart(lines []string, i int) bool {
+	line := lines[i]
+	trimmed := strings.TrimSpace(line)
+	return trimmed == "" || fenceRe.MatchString(line) || headingRe.MatchString(line) ||
+		ruleRe.MatchString(line) || strings.HasPrefix(trimmed, ">") || itemRe.MatchString(line)
+}
+
+// heading writes a heading of the level.
+func (r *renderer) heading(b *strings.Builder, level int, text string, width int) {
+	style := term.Style{Bold: true}
+	switch level {
+	case 1:
+		style = r.theme.Primary
+		style.Bold, style.Underline = true, true
+	case 2:
+		style = r.theme.Primary
+		style.Bold = true
+	}
+	r.wrap(b, r.inline(text, style), width, "", "")
+}
+
+// code returns a line of a code block.
+func (r *renderer) code(line string) string {
+	return strings.TrimRight("    "+r.theme.Accent.Render(strings.ReplaceAll(line, "\t", "    ")), " ") + "\n"
+}
+
+// fencedCode writes the fenced code block that starts at index i and
+// returns the index of the line after it.
+func (r *renderer) fencedCode(b *strings.Builder, lines []string, i int) int {
+	fence := fenceRe.FindStringSubmatch(lines[i])[1]
+	for i++; i < len(lines); i++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), fence) {
+			return i + 1
+		}
+		b.WriteString(r.code(lines[i]))
+	}
+	return i
+}
+
+// quote writes the block quote that starts at index i and returns
+// the index of the line after it.
+func (r *renderer) quote(b *strings.Builder, lines []string, i, width int) int {
+	var inner []string
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, ">") {
+			inner = append(inner, strings.TrimPrefix(strings.TrimPrefix(trimmed, ">"), " "))
+		} else if trimmed != "" && len(inner) > 0 && !r.blockStart(lines, i) {
+			// lazy continuation of a paragraph
+			inner = append(inner, trimmed)
+		} else {
+			break
+		}
+	}
+	bar := r.theme.Muted.Render("│")
+	for _, line := range strings.Split(strings.TrimRight(r.render(inner, width-2), "\n"), "\n") {
+		b.WriteString(strings.TrimRight(bar+" "+line, " ") + "\n")
+	}
+	return i
+}
+
+// item writes the list item that starts at index i (m are the submatches
+// of itemRe) and returns the index of the line after it.
+func (r *renderer) item(b *strings.Builder, lines []string, i int, m []string, width int) int {
+	indent := len(strings.ReplaceAll(m[1], "\t", "    "))
+	level := indent / 2
+	marker := m[2]
+	if strings.IndexAny(marker, "-*+") == 0 {
+		marker = bullets[level%len(bullets)]
+	}
+	text := []string{m[3]}
+	for i++; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" || r.blockStart(lines, i) {
+			break
+		}
+		text = append(text, strings.TrimSpace(line))
+	}
+	prefix := strings.Repeat("  ", level)
+	rest := prefix + strings.Repeat(" ", term.StringWidth(marker)+1)
+	r.wrap(b, r.inline(strings.Join(text, " "), term.Style{}), width, prefix+marker+" ", rest)
+	return i
+}
+
+// table writes the table that starts at index i and returns the index
+// of the line after it.
+func (r *renderer) table(b *strings.Builder, lines []string, i, width int) int {
+	header := r.cells(lines[i])
+	var align []term.Align
+	for _, spec := range splitRow(lines[i+1]) {
+		spec = strings.TrimSpace(spec)
+		switch {
+		case strings.HasPrefix(spec, ":") && strings.HasSuffix(spec, ":"):
+			align = append(align, term.AlignCenter)
+		case strings.HasSuffix(spec, ":"):
+			align = append(align, term.AlignRight)
+		default:
+			align = append(align, term.AlignLeft)
+		}
+	}
+	var rows [][]string
+	for i += 2; i < len(lines) && strings.Contains(lines[i], "|") && strings.TrimSpace(lines[i]) != ""; i++ {
+		rows = append(rows, r.cells(lines[i]))
+	}
+	b.WriteString(term.Table(header, rows, &term.TableOpt{Align: align, Width: width}))
+	return i
+}
+
+// cells returns the rendered cells of a row of a table.
+func (r *renderer) cells(line string) []string {
+	var cells []string
+	for _, cell := range splitRow(line) {
+		var sb strings.Builder
+		for i, w := range r.inline(strings.TrimSpace(cell), term.Style{}) {
+			if i > 0 {
+				sb.WriteString(" ")
+			}
+			sb.WriteString(w.text)
+		}
+		cells = append(cells, sb.String())
+	}
+	return cells
+}
+
+// splitRow splits a row of a table at the pipes (except \|).
+func splitRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+	var cells []string
+	start := 0
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '|':
+			cells = append(cells, strings.ReplaceAll(line[start:i], `\|`, "|"))
+			start = i + 1
+		}
+	}
+	return append(cells, strings.ReplaceAll(line[start:], `\|`, "|"))
+}
+
+// word is a rendered word of a paragraph.
+type word struct {
+	text  string // with escape sequences
+	width int
+}
+
+// wrap writes the words wrapped to the width; the first line starts with
+// first, the other lines with rest.
+func (r *renderer) wrap(b *strings.Builder, words []word, width int, first, rest string) {
+	prefix := first
+	line, lineWidth := prefix, term.StringWidth(prefix)
+	empty := true
+	for _, w := range words {
+		if !empty && lineWidth+1+w.width > width {
+			b.WriteString(line + "\n")
+			prefix = rest
+			line, lineWidth = prefix, term.StringWidth(prefix)
+			empty = true
+		}
+		if !empty {
+			line += " "
+			lineWidth++
+		}
+		line += w.text
+		lineWidth += w.width
+		empty = false
+	}
+	b.WriteString(strings.TrimRight(line, " ") + "\n")
+}
@@ -0,0 +1,68 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+// IDs of the messages that are shown by this package (see SetMessages).
+const (
+	MsgYesNo        = "yes-no"        // default: "yn" (the keys for yes and no in forms and when a password is saved)
+	MsgViInsert     = "vi-insert"     // default: "(ins) "
+	MsgViNormal     = "vi-normal"     // default: "(cmd) "
+	MsgSearch       = "search"        // default: "(reverse-i-search)`%s': "
+	MsgSearchFailed = "search-failed" // default: "(failed reverse-i-search)`%s': "
+	MsgInvalid      = "invalid"       // default: "  (%s)" (the error of an invalid input, see InputOpt.KeepInvalid)
+	MsgKeyBindings  = "key-bindings"  // default: "Key bindings (type to search, Esc to close): %s"
+	MsgStep         = "step"          // default: "Step %d of %d: %s" (see StepIndicator)
+	MsgConfirm      = "confirm"       // default: "Apply the answers, cancel or change a field [%s/number]? " (see Form.Confirm)
+	MsgSavePassword = "save-password" // default: "Save the password?" (see SetCredentialStore)
+	MsgOTPRemaining = "otp-remaining" // default: "  (%ds)" (the seconds remaining for a one-time code, see GetOTP)
+	MsgChallenge    = "challenge"     // default: "Type the characters above to continue: " (see Challenge)
+	MsgTableHelp    = "table-help"    // default: "↑↓ select  1-9 sort  / filter  Enter done" (see TableSelect)
+	MsgTableFilter  = "table-filter"  // default: "/%s" (the filter of TableSelect)
+	MsgLogFollow    = "log-follow"    // default: "[follow] %d lines  ↑↓ scroll  f follow  q quit" (see LogView)
+	MsgLogScroll    = "log-scroll"    // default: "[%d-%d of %d]  ↑↓ scroll  f follow  q quit" (see LogView)
+	MsgLogEnd       = "log-end"       // default: "  (end)" (appended if there are no more lines)
+	MsgHidden       = "hidden"        // default: "(hidden)" (see RedactHidden)
+)
+
+var defaultMessages = map[string]string{
+	MsgYesNo:        "yn",
+	MsgViInsert:     "(ins) ",
+	MsgViNormal:     "(cmd) ",
+	MsgSearch:       "(reverse-i-search)`%s': ",
+	MsgSearchFailed: "(failed reverse-i-search)`%s': ",
+	MsgInvalid:      "  (%s)",
+	MsgKeyBindings:  "Key bindings (type to search, Esc to close): %s",
+	MsgStep:         "Step %d of %d: %s",
+	MsgConfirm:      "Apply the answers, cancel or change a field [%s/number]? ",
+	MsgSavePassword: "Save the password?",
+	MsgOTPRemaining: "  (%ds)",
+	MsgChallenge:    "Type the characters above to continue: ",
+	MsgTableHelp:    "↑↓ select  1-9 sort  / filter  Enter done",
+	MsgTableFilter:  "/%s",
+	MsgLogFollow:    "[follow] %d lines  ↑↓ scroll  f follow  q quit",
+	MsgLogScroll:    "[%d-%d of %d]  ↑↓ scroll  f follow  q quit",
+	MsgLogEnd:       "  (end)",
+	MsgHidden:       "(hidden)",
+}
+
+var messages map[string]string
+
+// SetMessages sets translations for the messages that are shown by this
+// package; the keys are the message IDs (constants Msg...). Messages that
+// are not in m are shown in English. Messages with verbs (%s, %d) must
+// contain them in the translation too. nil removes all translations.
+//   term.SetMessages(map[string]string{
+//       term.MsgYesNo:    "jn",
+//       term.MsgViNormal: "(bef) ",
+//   })
+func SetMessages(m map[string]string) {
+	messages = m
+}
+
+// msg returns the message with the given ID.
+func msg(id string) string {
+	if s, ok := messages[id]; ok {
+		return s
+	}
+	return defaultMessages[id]
+}
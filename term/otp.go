@@ -0,0 +1,82 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// OTPOpt contains the options for the GetOTP function.
+type OTPOpt struct {
+	Prompt string        // shown before the input
+	Length uint          // number of digits (default: 6)
+	Period time.Duration // time step of TOTP codes (e.g. 30s); 0: no timing hint
+}
+
+// otpTick is the interval in which the timing hint of GetOTP is updated.
+const otpTick = 250 * time.Millisecond
+
+// GetOTP gets a one-time code (e.g. for two-factor authentication) from
+// a terminal. Only digits are accepted; the input ends as soon as all digits
+// are typed. Backspace deletes the last digit. If opt.Period is set, the seconds
+// remaining in the current TOTP time step (counted from the Unix epoch like
+// in RFC 6238) are shown after the input and an incomplete input is cleared
+// when the time step ends, so that the code of the next step can be typed.
+//   code, err := term.GetOTP(&term.OTPOpt{Prompt: "Code: ", Period: 30 * time.Second})
+//   -> Code: 123___  (17s)
+// If Esc is typed and SetCancelOnEsc is enabled, ErrCanceled is returned.
+// opt may be nil.
+// It panics if stdin and stdout are not connected to a terminal.
+func GetOTP(opt *OTPOpt) (string, error) {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	checkIsTerminal()
+	if opt == nil {
+		opt = &OTPOpt{}
+	}
+	length := int(opt.Length)
+	if length == 0 {
+		length = 6
+	}
+	var tick time.Duration
+	step := func() int64 { return 0 }
+	if opt.Period > 0 {
+		tick = otpTick
+		step = func() int64 { return time.Now().UnixNano() / int64(opt.Period) }
+	}
+	start := step()
+	var code []byte
+	err := runWidgetTick(func() []string {
+		line := opt.Prompt + string(code) + strings.Repeat("_", length-len(code))
+		if opt.Period > 0 {
+			remaining := opt.Period - time.Duration(time.Now().UnixNano()%int64(opt.Period))
+			line += theme.Muted.Render(fmt.Sprintf(msg(MsgOTPRemaining), (remaining+time.Second-1)/time.Second))
+		}
+		return []string{line}
+	}, func(key string) (bool, error) {
+		if s := step(); s != start {
+			// the time step ended: the code is no longer valid
+			start = s
+			if len(code) > 0 {
+				code = code[:0]
+				writeOut(func() { out.WriteString(bell) })
+			}
+		}
+		switch {
+		case len(key) == 1 && key[0] >= '0' && key[0] <= '9':
+			code = append(code, key[0])
+			return len(code) == length, nil
+		case (key == "\x7F" || key == "\x08") && len(code) > 0:
+			code = code[:len(code)-1]
+		case key == "\x1b" && cancelOnEsc:
+			return true, ErrCanceled
+		}
+		return false, nil
+	}, tick)
+	if err != nil {
+		return "", err
+	}
+	return string(code), nil
+}
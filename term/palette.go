@@ -0,0 +1,93 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// ANSI escape codes: set/query palette color (OSC 4;<n>;<spec> BEL),
+//                    reset palette (OSC 104 BEL).
+const (
+	paletteSeq   = "\x1b]4;%d;%s\x07"
+	paletteReset = "\x1b]104\x07"
+)
+
+// paletteChanged is 1 if SetPaletteColor was called.
+var paletteChanged int32
+
+// SetPaletteColor redefines the color with index n in the palette of
+// the terminal (see Index). Terminals that do not support it ignore it.
+// The colors should be reset with ResetPalette before the program exits;
+// RestoreOnExit resets them if the program is terminated by a signal or
+// a panic.
+//   term.SetPaletteColor(1, 0xcc, 0x33, 0x33) // a softer red
+//   defer term.ResetPalette()
+func SetPaletteColor(n, r, g, b uint8) {
+	atomic.StoreInt32(&paletteChanged, 1)
+	writeOut(func() {
+		fmt.Fprintf(out, paletteSeq, n, fmt.Sprintf("rgb:%02x/%02x/%02x", r, g, b))
+	})
+}
+
+// ResetPalette resets all colors of the palette to the defaults of
+// the terminal.
+func ResetPalette() {
+	atomic.StoreInt32(&paletteChanged, 0)
+	writeOut(func() { out.WriteString(paletteReset) })
+}
+
+// QueryPaletteColor returns the color with index n in the palette of
+// the terminal. It panics if stdin and stdout are not connected to
+// a terminal and returns ErrNoResponse if the terminal does not support
+// the query.
+func QueryPaletteColor(n uint8) (r, g, b uint8, err error) {
+	return queryColor(fmt.Sprintf(paletteSeq, n, "?"), fmt.Sprintf("\x1b]4;%d;rgb:", n))
+}
+
+// ANSI escape code: set/query background color (OSC 11;<spec> BEL).
+const backgroundSeq = "\x1b]11;%s\x07"
+
+// QueryBackground returns the background color of the terminal.
+// It panics if stdin and stdout are not connected to a terminal and
+// returns ErrNoResponse if the terminal does not support the query.
+func QueryBackground() (r, g, b uint8, err error) {
+	return queryColor(fmt.Sprintf(backgroundSeq, "?"), "\x1b]11;rgb:")
+}
+
+// queryColor sends the query seq and returns the color in the response
+// that starts with prefix followed by the color in the format r/g/b.
+func queryColor(seq, prefix string) (r, g, b uint8, err error) {
+	// the response to DA1 marks the end
+	resp, err := queryDA(seq)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	i := bytes.Index(resp, []byte(prefix))
+	if i < 0 {
+		return 0, 0, 0, ErrNoResponse
+	}
+	spec := string(resp[i+len(prefix):])
+	if j := strings.IndexAny(spec, "\x07\x1b"); j >= 0 {
+		spec = spec[:j]
+	}
+	parts := strings.Split(spec, "/")
+	if len(parts) != 3 {
+		return 0, 0, 0, fmt.Errorf("invalid color: %q", spec)
+	}
+	var rgb [3]uint8
+	for k, p := range parts {
+		v, err := strconv.ParseUint(p, 16, 16)
+		if err != nil || len(p) == 0 || len(p) > 4 {
+			return 0, 0, 0, fmt.Errorf("invalid color: %q", spec)
+		}
+		// scale 1 to 4 hex digits to 8 bits
+		max := uint64(1)<<(4*uint(len(p))) - 1
+		rgb[k] = uint8((v*255 + max/2) / max)
+	}
+	return rgb[0], rgb[1], rgb[2], nil
+}
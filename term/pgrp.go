@@ -0,0 +1,77 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"os"
+	"os/signal"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// GetPgrp returns the ID of the foreground process group of the terminal.
+// It returns an error if the file descriptor fd is not connected to
+// a terminal.
+func GetPgrp(fd uintptr) (int, error) {
+	return unix.IoctlGetInt(int(fd), unix.TIOCGPGRP)
+}
+
+// SetPgrp makes the process group with the ID pgrp the foreground process
+// group of the terminal, which must be the controlling terminal of the
+// calling process. If the calling process is in the background, it is
+// stopped by the signal SIGTTOU unless the signal is ignored (see
+// signal.Ignore).
+func SetPgrp(fd uintptr, pgrp int) error {
+	return unix.IoctlSetPointerInt(int(fd), unix.TIOCSPGRP, pgrp)
+}
+
+// GetSid returns the ID of the session the terminal belongs to (the process
+// ID of the session leader). It returns an error if the file descriptor fd
+// is not connected to a terminal.
+func GetSid(fd uintptr) (int, error) {
+	return getSid(fd)
+}
+
+// IsForeground returns whether the calling process is in the foreground
+// process group of the terminal. A process in the background is stopped
+// by the signal SIGTTIN if it reads from the terminal. It returns false
+// if the file descriptor fd is not connected to a terminal.
+//   if !term.IsForeground(os.Stdin.Fd()) {
+//       // do not prompt
+//   }
+func IsForeground(fd uintptr) bool {
+	pgrp, err := GetPgrp(fd)
+	return err == nil && pgrp == ownPgrp()
+}
+
+// ownPgrp returns the process group ID of the calling process.
+func ownPgrp() int {
+	pgrp, _ := unix.Getpgid(0)
+	return pgrp
+}
+
+// WaitForeground waits until the calling process is in the foreground
+// process group of the terminal (e.g. after the job was continued with
+// "fg" in the shell). It returns an error if the file descriptor fd is not
+// connected to a terminal.
+func WaitForeground(fd uintptr) error {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, unix.SIGCONT)
+	defer signal.Stop(sigCh)
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		pgrp, err := GetPgrp(fd)
+		if err != nil {
+			return err
+		}
+		if pgrp == ownPgrp() {
+			return nil
+		}
+		select {
+		case <-sigCh:
+		case <-ticker.C:
+		}
+	}
+}
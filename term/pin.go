@@ -0,0 +1,103 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// PINOpt contains the options for the GetPIN function.
+type PINOpt struct {
+	Prompt  string // shown before the input
+	Shuffle bool   // the digits are typed with shuffled keys shown on a keypad
+}
+
+// GetPIN gets a PIN with exactly length digits from a terminal. Only digits
+// are accepted and the input is masked; it ends as soon as length digits are
+// typed. Backspace deletes the last digit. With opt.Shuffle, a keypad with
+// the digits and the keys for typing them is shown below the input, so that
+// someone who watches the keyboard does not learn the PIN:
+//   1 [7]  2 [3]  3 [0]
+//   4 [1]  5 [9]  6 [2]
+//   7 [8]  8 [4]  9 [6]
+//          0 [5]
+// The keys are shuffled for each call. If Esc is typed and SetCancelOnEsc
+// is enabled, ErrCanceled is returned. opt may be nil.
+// It panics if stdin and stdout are not connected to a terminal or
+// if length is 0.
+func GetPIN(length uint, opt *PINOpt) (string, error) {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	checkIsTerminal()
+	if length == 0 {
+		panic("length must be > 0")
+	}
+	if opt == nil {
+		opt = &PINOpt{}
+	}
+	keys := []byte("0123456789") // keys[d] is the key for the digit d
+	if opt.Shuffle {
+		if err := shuffleKeys(keys); err != nil {
+			return "", err
+		}
+	}
+	var pin []byte
+	err := runWidget(func() []string {
+		lines := []string{opt.Prompt + strings.Repeat(string(maskChar), len(pin)) +
+			strings.Repeat("_", int(length)-len(pin))}
+		if opt.Shuffle {
+			lines = append(lines, pinKeypad(keys)...)
+		}
+		return lines
+	}, func(key string) (bool, error) {
+		if len(key) == 1 {
+			if i := strings.IndexByte(string(keys), key[0]); i >= 0 {
+				pin = append(pin, '0'+byte(i))
+				return uint(len(pin)) == length, nil
+			}
+			if (key[0] == 0x7F || key[0] == 0x08) && len(pin) > 0 {
+				pin = pin[:len(pin)-1]
+				return false, nil
+			}
+		}
+		if key == "\x1b" && cancelOnEsc {
+			return true, ErrCanceled
+		}
+		return false, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(pin), nil
+}
+
+// shuffleKeys shuffles the keys with random numbers from crypto/rand.
+func shuffleKeys(keys []byte) error {
+	for i := len(keys) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return err
+		}
+		keys[i], keys[j.Int64()] = keys[j.Int64()], keys[i]
+	}
+	return nil
+}
+
+// pinKeypad returns the lines of the keypad in the layout of a phone.
+func pinKeypad(keys []byte) []string {
+	cell := func(d int) string {
+		return fmt.Sprintf("%d [%c]", d, keys[d])
+	}
+	var lines []string
+	for row := 0; row < 3; row++ {
+		var cells []string
+		for col := 1; col <= 3; col++ {
+			cells = append(cells, cell(row*3+col))
+		}
+		lines = append(lines, strings.Join(cells, "  "))
+	}
+	return append(lines, "       "+cell(0))
+}
@@ -0,0 +1,78 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/andreas19/go-term/term/ansi"
+)
+
+// Test strings of SupportsUnicode and SupportsEmoji.
+const (
+	unicodeProbe = "é中" // a 2-byte character (width 1) and a wide character
+	emojiProbe   = "😀"  // an emoji (width 2)
+)
+
+var (
+	probeMu    sync.Mutex
+	probeWidth = map[string]int{}
+)
+
+// SupportsUnicode reports whether the terminal renders UTF-8 text with
+// the widths this package computes (see StringWidth), including wide
+// East Asian characters. A test string is printed and the advance of
+// the cursor is measured (the string is erased again). Programs can use
+// ASCII instead of box drawing characters or symbols if it returns false.
+// The result is cached. It returns ErrNoResponse if the terminal does not
+// respond within the timeout (see SetQueryTimeout).
+// It panics if stdin and stdout are not connected to a terminal.
+func SupportsUnicode() (bool, error) {
+	w, err := probeAdvance(unicodeProbe)
+	return err == nil && w == StringWidth(unicodeProbe), err
+}
+
+// SupportsEmoji reports like SupportsUnicode whether the terminal renders
+// emojis with a width of 2 columns.
+func SupportsEmoji() (bool, error) {
+	w, err := probeAdvance(emojiProbe)
+	return err == nil && w == StringWidth(emojiProbe), err
+}
+
+// ANSI escape code: Cursor Horizontal Absolute (CHA: ESC[<n>G),
+//                   Erase in Line (EL: ESC[K).
+
+// probeAdvance prints s between two cursor position queries and returns
+// the number of columns the cursor advanced. The result is cached.
+func probeAdvance(s string) (int, error) {
+	probeMu.Lock()
+	defer probeMu.Unlock()
+	if w, ok := probeWidth[s]; ok {
+		return w, nil
+	}
+	var cols []int
+	_, err := query(cursorPosQuery+s+cursorPosQuery, func(resp []byte) bool {
+		var p ansi.Parser
+		cols = cols[:0]
+		for _, seq := range p.Feed(resp) {
+			if seq.Type == ansi.CSI && seq.Prefix == 0 && seq.Final == 'R' {
+				cols = append(cols, seq.Param(1, 1))
+			}
+		}
+		return len(cols) == 2
+	})
+	if len(cols) > 0 {
+		writeOut(func() { fmt.Fprintf(out, "\x1b[%dG\x1b[K", cols[0]) })
+	}
+	if err != nil {
+		return 0, err
+	}
+	w := cols[1] - cols[0]
+	if w < 0 {
+		// the test string was wrapped
+		return 0, fmt.Errorf("cannot measure the width of %q", s)
+	}
+	probeWidth[s] = w
+	return w, nil
+}
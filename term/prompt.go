@@ -0,0 +1,52 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Prompt is a reusable prompt for the Input function. All settings
+// are evaluated once when it is created.
+//   p, err := term.NewPrompt("Value: ", &v, &term.InputOpt{Default: 0})
+//   ...
+//   for {
+//       if err := p.Run(); err != nil {
+//           break
+//       }
+//       ...
+//   }
+type Prompt struct {
+	text string
+	in   interface{}
+	opt  InputOpt
+	conv func(string) error
+}
+
+// NewPrompt returns a new Prompt. The arguments are the same as for
+// the function Input; opt is copied and may be nil.
+// It returns an error if in is not a pointer.
+func NewPrompt(prompt string, in interface{}, opt *InputOpt) (*Prompt, error) {
+	if val := reflect.ValueOf(in); val.Kind() != reflect.Ptr {
+		return nil, fmt.Errorf("type of 'in' not a pointer: %s", val.Type())
+	}
+	p := &Prompt{text: prompt, in: in}
+	if opt != nil {
+		p.opt = *opt
+	}
+	p.conv = converter(in, &p.opt)
+	return p, nil
+}
+
+// Run shows the prompt and assigns the input to the variable given
+// to NewPrompt like the function Input.
+// It panics if stdin and stdout are not connected to a terminal (unless
+// in non-interactive mode) or if the default value or the return value of
+// the ConvFunc option are not assignable to the variable.
+func (p *Prompt) Run() error {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	checkCanInput()
+	return input(p.text, p.in, &p.opt, p.conv)
+}
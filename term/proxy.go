@@ -0,0 +1,121 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/signal"
+
+	"golang.org/x/sys/unix"
+)
+
+// ProxyOpt are the options for Proxy.
+type ProxyOpt struct {
+	// the character that detaches the proxy (default: ^])
+	Escape byte
+	// the escape character is sent like all other characters
+	NoEscape bool
+	// called with the size (width, height) of the local terminal when
+	// the proxy starts and when the size changes (default: the size of remote
+	// is set if it is a terminal, e.g. a pty)
+	Resize func(width, height uint16)
+}
+
+// proxyEscape is the default escape character of Proxy (^]).
+const proxyEscape = 0x1D
+
+// proxyPoll is the interval in milliseconds in which Proxy checks
+// whether it is canceled while the local terminal is idle.
+const proxyPoll = 100
+
+// Proxy puts the local terminal into raw mode and copies the bytes typed
+// on it to remote and the bytes read from remote to it until remote reaches
+// EOF, the escape character (see ProxyOpt) is typed or the context is
+// canceled. It is the core of a minimal bridge to a pty, a serial port
+// (see OpenSerial) or a network connection:
+//   ptmx := ... // the master side of the pty of a child process
+//   err := term.Proxy(context.Background(), os.Stdin, ptmx, nil)
+// The terminal state is restored afterwards. It returns nil if remote
+// reached EOF or the escape character was typed, the error of the context
+// if it was canceled and otherwise the error of reading or writing. Reading
+// from remote is only stopped when remote is closed, so the caller should
+// close it after Proxy returned.
+func Proxy(ctx context.Context, local *os.File, remote io.ReadWriter, opt *ProxyOpt) error {
+	if opt == nil {
+		opt = &ProxyOpt{}
+	}
+	esc := opt.Escape
+	if esc == 0 {
+		esc = proxyEscape
+	}
+	fd := int(local.Fd())
+	restore, err := MakeRaw(local.Fd())
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	resize := func() {
+		if opt.Resize == nil {
+			if f, ok := remote.(*os.File); ok && IsTerminal(f.Fd()) {
+				InheritSize(local.Fd(), f.Fd())
+			}
+			return
+		}
+		if ws, err := unix.IoctlGetWinsize(fd, unix.TIOCGWINSZ); err == nil {
+			opt.Resize(ws.Col, ws.Row)
+		}
+	}
+	resize()
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, unix.SIGWINCH)
+	defer signal.Stop(sigCh)
+
+	remoteDone := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(local, remote)
+		var errno unix.Errno
+		if errors.As(err, &errno) && errno == unix.EIO {
+			// the master side of a pty returns EIO when the slave side
+			// is closed
+			err = nil
+		}
+		remoteDone <- err
+	}()
+
+	buf := make([]byte, 4096)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-remoteDone:
+			return err
+		case <-sigCh:
+			resize()
+		default:
+		}
+		fds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLIN}}
+		if n, err := unix.Poll(fds, proxyPoll); err == unix.EINTR || err == nil && n == 0 {
+			continue
+		} else if err != nil {
+			return err
+		}
+		n, err := local.Read(buf)
+		if err != nil {
+			return err
+		}
+		b := buf[:n]
+		i := bytes.IndexByte(b, esc)
+		if !opt.NoEscape && i >= 0 {
+			_, err := remote.Write(b[:i])
+			return err
+		}
+		if _, err := remote.Write(b); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"errors"
+	"time"
+
+	"github.com/andreas19/go-term/term/ansi"
+	"golang.org/x/sys/unix"
+)
+
+// ErrNoResponse is returned if the terminal does not respond to a query
+// within the timeout.
+var ErrNoResponse = errors.New("no response from terminal")
+
+var queryTimeout = 500 * time.Millisecond
+
+// SetQueryTimeout sets the time to wait for the response to a query
+// (e.g. by Identify); the default is 500ms.
+func SetQueryTimeout(d time.Duration) {
+	queryTimeout = d
+}
+
+// ANSI escape code: Primary Device Attributes (DA1) ESC[c;
+// all terminals respond to it, so it is sent after other queries
+// to detect that they are not supported.
+const primaryDA = "\x1b[c"
+
+// query writes the query seq to the terminal and reads the response until
+// done returns true for the bytes read so far. Echo and canonical mode
+// are disabled while waiting. Keys typed in the meantime are discarded.
+func query(seq string, done func(resp []byte) bool) ([]byte, error) {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	checkIsTerminal()
+	restore, err := noEcho(int(inFile.Fd()))
+	if err != nil {
+		return nil, err
+	}
+	defer restore()
+	writeOut(func() { out.WriteString(seq) })
+	var r keyReader
+	deadline := time.Now().Add(queryTimeout)
+	for !done(r.pending) {
+		timeout := time.Until(deadline)
+		if timeout <= 0 {
+			return r.pending, ErrNoResponse
+		}
+		if _, err := r.more(timeout); err != nil {
+			return r.pending, err
+		}
+	}
+	return r.pending, nil
+}
+
+// noEcho disables echo and canonical mode of the terminal and returns
+// a function that restores the previous state.
+func noEcho(fd int) (func(), error) {
+	termios, err := unix.IoctlGetTermios(fd, termiosGet)
+	if err != nil {
+		return nil, err
+	}
+	old := *termios
+	termios.Lflag &^= unix.ECHO | unix.ICANON
+	termios.Cc[unix.VMIN] = 1
+	termios.Cc[unix.VTIME] = 0
+	if err = unix.IoctlSetTermios(fd, termiosSet, termios); err != nil {
+		return nil, err
+	}
+	return func() { unix.IoctlSetTermios(fd, termiosSet, &old) }, nil
+}
+
+// queryDA writes the query seq followed by DA1 to the terminal and
+// returns the response including the response to DA1. The response to
+// seq is missing if the terminal does not support the query.
+func queryDA(seq string) ([]byte, error) {
+	return query(seq+primaryDA, func(resp []byte) bool {
+		_, ok := parseCSI(resp, '?', 'c')
+		return ok
+	})
+}
+
+// parseCSI returns the parameters of the first control sequence in b
+// with the private marker prefix and the final byte; ok is false if there
+// is no such sequence.
+func parseCSI(b []byte, prefix, final byte) (params []int, ok bool) {
+	var p ansi.Parser
+	for _, s := range p.Feed(b) {
+		if s.Type == ansi.CSI && s.Prefix == prefix && s.Final == final {
+			params = make([]int, len(s.Params))
+			for i := range params {
+				params[i] = s.Param(i, 0)
+			}
+			return params, true
+		}
+	}
+	return nil, false
+}
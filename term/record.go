@@ -0,0 +1,87 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"encoding/json"
+	"io"
+)
+
+type recordEntry struct {
+	Key      string `json:"key"`
+	Answer   string `json:"answer"`
+	Redacted bool   `json:"redacted,omitempty"`
+}
+
+var (
+	recorder      *json.Encoder
+	recordSecrets bool
+)
+
+// Record starts recording the answers to all prompts of the functions that
+// support the non-interactive mode (see function SetAnswers) to w, one JSON
+// object per line. An empty answer means that the default value was used.
+// If w is nil, the recording stops. Answers that were not echoed (e.g.
+// passwords) are recorded as redacted entries without the answer unless
+// enabled with RecordSecrets; they are not replayed by ReplayAnswers.
+func Record(w io.Writer) {
+	if w == nil {
+		recorder = nil
+	} else {
+		recorder = json.NewEncoder(w)
+	}
+}
+
+// RecordSecrets sets whether answers that were not echoed are recorded
+// in cleartext (default: false). The recording must then be protected
+// like the secrets themselves.
+func RecordSecrets(b bool) {
+	recordSecrets = b
+}
+
+func recordAnswer(prompt string, opt *InputOpt, s string) {
+	if recorder == nil {
+		return
+	}
+	entry := recordEntry{Key: inputKey(prompt, opt), Answer: s}
+	if opt.Echo != EchoNormal && !recordSecrets {
+		entry.Answer, entry.Redacted = "", true
+	}
+	recorder.Encode(entry)
+}
+
+type replayAnswers map[string][]string
+
+func (r replayAnswers) Answer(key string) (string, bool) {
+	q := r[key]
+	if len(q) == 0 {
+		return "", false
+	}
+	r[key] = q[1:]
+	return q[0], true
+}
+
+// ReplayAnswers returns an AnswerProvider with the answers recorded with
+// the function Record. If there are several answers for the same key,
+// they are returned in the order in which they were recorded.
+//   f, _ := os.Open("session.rec")
+//   p, err := term.ReplayAnswers(f)
+//   ...
+//   term.SetAnswers(p)
+//   term.SetNonInteractive(true)
+func ReplayAnswers(r io.Reader) (AnswerProvider, error) {
+	answers := replayAnswers{}
+	dec := json.NewDecoder(r)
+	for {
+		var entry recordEntry
+		if err := dec.Decode(&entry); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if !entry.Redacted {
+			answers[entry.Key] = append(answers[entry.Key], entry.Answer)
+		}
+	}
+	return answers, nil
+}
@@ -0,0 +1,199 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"strings"
+	"unicode"
+)
+
+// softHyphen marks a position where a word may be hyphenated.
+const softHyphen = "\u00ad"
+
+// Reflow wraps the paragraphs of text (e.g. the help of a program) to width
+// columns; if width is 0, the width of the terminal is used (80 if stdout is
+// not connected to a terminal). The lines of a paragraph are joined before
+// they are wrapped. Paragraphs are separated by empty lines, which are kept.
+// Items of lists (starting with "-", "*", "+", "•", "1." or "1)") start
+// new paragraphs and their lines are indented under the text of the item.
+// Paragraphs that start with a line that is indented by 4 or more columns
+// (e.g. examples) are not changed. Words that contain soft hyphens (U+00AD)
+// are hyphenated there if they do not fit into a line; the soft hyphens
+// are removed from the result.
+//   fmt.Print(term.Reflow(usage, 0))
+func Reflow(text string, width int) string {
+	width = chartWidth(width)
+	var b strings.Builder
+	var words []string
+	var first, rest string // the prefixes of the first and the other lines
+	flush := func() {
+		if words == nil {
+			return
+		}
+		for i, line := range wrapText(strings.Join(words, " "), width-indentWidth(rest)) {
+			prefix := rest
+			if i == 0 {
+				prefix = first
+			}
+			b.WriteString(strings.TrimRight(prefix+line, " ") + "\n")
+		}
+		words = nil
+	}
+	verbatim := false
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	for _, line := range lines {
+		trimmed := strings.TrimLeft(line, " \t")
+		indent := line[:len(line)-len(trimmed)]
+		marker := listMarker(trimmed)
+		switch {
+		case trimmed == "":
+			flush()
+			verbatim = false
+			b.WriteString("\n")
+		case verbatim || words == nil && marker == "" && indentWidth(indent) >= 4:
+			verbatim = true
+			b.WriteString(strings.TrimRight(line, " \t") + "\n")
+		case marker != "":
+			flush()
+			first = indent + marker
+			rest = indent + strings.Repeat(" ", StringWidth(marker))
+			words = strings.Fields(trimmed[len(marker):])
+		case words == nil:
+			first, rest = indent, indent
+			words = strings.Fields(trimmed)
+		default:
+			words = append(words, strings.Fields(trimmed)...)
+		}
+	}
+	flush()
+	s := b.String()
+	if !strings.HasSuffix(text, "\n") {
+		s = strings.TrimSuffix(s, "\n")
+	}
+	return s
+}
+
+// listMarker returns the marker of a list item at the start of s
+// including the following spaces or "" if s is not a list item.
+func listMarker(s string) string {
+	n := 0
+	switch {
+	case strings.HasPrefix(s, "•"):
+		n = len("•")
+	case strings.HasPrefix(s, "-"), strings.HasPrefix(s, "*"), strings.HasPrefix(s, "+"):
+		n = 1
+	default:
+		for n < len(s) && s[n] >= '0' && s[n] <= '9' {
+			n++
+		}
+		if n == 0 || n > 3 || n == len(s) || s[n] != '.' && s[n] != ')' {
+			return ""
+		}
+		n++
+	}
+	rest := strings.TrimLeft(s[n:], " ")
+	if len(rest) == len(s)-n || rest == "" {
+		// the marker must be followed by spaces and text
+		return ""
+	}
+	return s[:len(s)-len(rest)]
+}
+
+// indentWidth returns the width of an indentation with spaces and tabs
+// (tab stops every 8 columns).
+func indentWidth(s string) int {
+	w := 0
+	for _, ch := range s {
+		if ch == '\t' {
+			w += 8 - w%8
+		} else {
+			w++
+		}
+	}
+	return w
+}
+
+// wrapText splits s into lines that are at most width columns wide.
+// Lines are wrapped at spaces; words that are longer are hyphenated at
+// soft hyphens (if possible) or split. Newlines in s are kept.
+func wrapText(s string, width int) []string {
+	if width < 1 {
+		width = 1
+	}
+	var lines []string
+	for _, para := range strings.Split(s, "\n") {
+		var line strings.Builder
+		lineWidth := 0
+		newLine := func() {
+			lines = append(lines, line.String())
+			line.Reset()
+			lineWidth = 0
+		}
+		for _, word := range strings.Fields(para) {
+			w := StringWidth(strings.ReplaceAll(word, softHyphen, ""))
+			if lineWidth > 0 && lineWidth+1+w > width {
+				if i := hyphenate(word, width-lineWidth-1); i > 0 {
+					line.WriteString(" " + strings.ReplaceAll(word[:i], softHyphen, "") + "-")
+					word = word[i+len(softHyphen):]
+					w = StringWidth(strings.ReplaceAll(word, softHyphen, ""))
+				}
+				newLine()
+			}
+			if lineWidth > 0 {
+				line.WriteByte(' ')
+				lineWidth++
+			}
+			for w > width-lineWidth {
+				if i := hyphenate(word, width-lineWidth); i > 0 {
+					line.WriteString(strings.ReplaceAll(word[:i], softHyphen, "") + "-")
+					newLine()
+					word = word[i+len(softHyphen):]
+					w = StringWidth(strings.ReplaceAll(word, softHyphen, ""))
+					continue
+				}
+				word = strings.ReplaceAll(word, softHyphen, "")
+				// split the word at the last grapheme cluster that fits
+				b := []byte(word)
+				n := 0
+				for n < len(b) {
+					l := graphemeLen(b[n:])
+					if lineWidth+bytesWidth(b[:n+l]) > width {
+						break
+					}
+					n += l
+				}
+				if n == 0 && lineWidth == 0 {
+					n = graphemeLen(b)
+				}
+				line.WriteString(word[:n])
+				newLine()
+				word = word[n:]
+				w = StringWidth(word)
+			}
+			line.WriteString(strings.ReplaceAll(word, softHyphen, ""))
+			lineWidth += w
+		}
+		lines = append(lines, line.String())
+	}
+	return lines
+}
+
+// hyphenate returns the index of the last soft hyphen in word where the part
+// before it and a hyphen fit into width columns or -1.
+func hyphenate(word string, width int) int {
+	best := -1
+	for i := strings.Index(word, softHyphen); i >= 0; {
+		if StringWidth(strings.ReplaceAll(word[:i], softHyphen, ""))+1 > width {
+			break
+		}
+		if strings.IndexFunc(word[:i], unicode.IsLetter) >= 0 {
+			best = i
+		}
+		j := strings.Index(word[i+len(softHyphen):], softHyphen)
+		if j < 0 {
+			break
+		}
+		i += len(softHyphen) + j
+	}
+	return best
+}
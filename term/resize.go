@@ -0,0 +1,95 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// errResized is returned by keyReader.wait if the window size changed.
+var errResized = errors.New("window resized")
+
+var (
+	resizeOnce    sync.Once
+	resizePipe    [2]int
+	resizeEvents  int32
+	resizeDelayNs = int64(50 * time.Millisecond)
+)
+
+// EnableResizeEvents sets whether ReadEvent returns Resized events when
+// the size of the terminal window changes (signal SIGWINCH). The events
+// are coalesced (see SetResizeDelay).
+func EnableResizeEvents(b bool) {
+	if b {
+		resizeOnce.Do(func() {
+			if err := unix.Pipe(resizePipe[:]); err != nil {
+				resizePipe = [2]int{-1, -1}
+				return
+			}
+			unix.SetNonblock(resizePipe[0], true)
+			unix.SetNonblock(resizePipe[1], true)
+			ch := make(chan os.Signal, 1)
+			signal.Notify(ch, unix.SIGWINCH)
+			go func() {
+				for range ch {
+					unix.Write(resizePipe[1], []byte{0})
+				}
+			}()
+		})
+	}
+	var v int32
+	if b && resizePipe[0] >= 0 {
+		v = 1
+	}
+	atomic.StoreInt32(&resizeEvents, v)
+}
+
+// SetResizeDelay sets how resize events are coalesced: a Resized event is
+// returned when the size has not changed for the delay d (default: 50ms),
+// but at least every 4*d while the size keeps changing, e.g. while the user
+// drags the edge of the window. With 0 each change is returned at once.
+func SetResizeDelay(d time.Duration) {
+	atomic.StoreInt64(&resizeDelayNs, int64(d))
+}
+
+// drainResize discards the pending notifications and returns whether
+// there were any.
+func drainResize() bool {
+	var buf [64]byte
+	found := false
+	for {
+		n, err := unix.Read(resizePipe[0], buf[:])
+		if n <= 0 || err != nil {
+			return found
+		}
+		found = true
+	}
+}
+
+// waitResize waits until the size of the window has not changed for
+// the resize delay, but at most 4 times the delay.
+func waitResize() {
+	delay := time.Duration(atomic.LoadInt64(&resizeDelayNs))
+	end := time.Now().Add(4 * delay)
+	for {
+		t := time.Until(end)
+		if t > delay {
+			t = delay
+		}
+		if t <= 0 {
+			return
+		}
+		fds := []unix.PollFd{{Fd: int32(resizePipe[0]), Events: unix.POLLIN}}
+		if n, err := unix.Poll(fds, int(t/time.Millisecond)); err == nil && n == 0 {
+			return
+		}
+		drainResize()
+	}
+}
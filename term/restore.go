@@ -0,0 +1,82 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"os"
+	"os/signal"
+	"sync/atomic"
+
+	"golang.org/x/sys/unix"
+)
+
+// ANSI escape codes: Reset SGR (ESC[0m), Show cursor (ESC[?25h),
+//                    disable mouse tracking (ESC[?1000l ... ESC[?1006l),
+//                    leave alternate screen (ESC[?1049l),
+//                    default cursor shape (ESC[0 q).
+const resetSeq = "\x1b[0m\x1b[?25h\x1b[?1000l\x1b[?1002l\x1b[?1003l\x1b[?1006l\x1b[?1049l" +
+	"\x1b[0 q" + bracketedPasteOff + focusEventsOff
+
+// RestoreOnExit saves the current state of the terminal connected to stdout
+// and registers handlers for SIGINT, SIGTERM and SIGHUP that restore it and
+// exit the program with status 128 + signal number. Restoring means setting
+// the saved termios state, showing the cursor with the default shape,
+// disabling mouse tracking and focus events, leaving the alternate screen,
+// resetting all text attributes, the palette if it was changed with
+// SetPaletteColor and the keyboard protocol (see EnableKeyboardEnhancement).
+//
+// The returned function must be deferred in the main goroutine. It restores
+// the terminal if the goroutine panics (and then panics again with the same
+// value) and removes the signal handlers.
+//   func main() {
+//       defer term.RestoreOnExit()()
+//       ...
+//   }
+// Nothing is done if stdout (or the prompt output, see SetPromptOutput)
+// is not connected to a terminal.
+func RestoreOnExit() func() {
+	outMu.Lock()
+	f := outFile
+	outMu.Unlock()
+	fd := int(f.Fd())
+	termios, err := unix.IoctlGetTermios(fd, termiosGet)
+	if err != nil {
+		return func() {}
+	}
+	// out is not used, because outMu may be locked if a panic occurs
+	restore := func() {
+		unix.IoctlSetTermios(fd, termiosSet, termios)
+		f.WriteString(resetSeq)
+		if atomic.LoadInt32(&paletteChanged) != 0 {
+			f.WriteString(paletteReset)
+		}
+		f.WriteString(keyboardOffSeq())
+	}
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, unix.SIGINT, unix.SIGTERM, unix.SIGHUP)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case sig := <-sigCh:
+				if sig == unix.SIGINT && atomic.LoadInt32(&runningCommand) != 0 {
+					// the command started with RunCommand handles it
+					continue
+				}
+				writeOut(func() {}) // flush pending output
+				restore()
+				os.Exit(128 + int(sig.(unix.Signal)))
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(sigCh)
+		close(done)
+		if r := recover(); r != nil {
+			restore()
+			panic(r)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"sync/atomic"
+
+	"golang.org/x/sys/unix"
+)
+
+// suspendModes are the DEC private modes that RunCommand resets while
+// the command is running: alternate screen, mouse tracking, bracketed paste
+// and focus events.
+var suspendModes = []int{1049, 1000, 1002, 1003, 1006, 2004, 1004}
+
+// ANSI escape codes: set/reset DEC private mode (ESC[?<n>h, ESC[?<n>l);
+// mode 25 shows the cursor.
+const (
+	setMode   = "\x1b[?%dh"
+	resetMode = "\x1b[?%dl"
+)
+
+// runningCommand is 1 while RunCommand runs a command.
+var runningCommand int32
+
+// RunCommand runs the command cmd attached to the terminal, e.g. $EDITOR
+// or $PAGER. If cmd.Stdin, cmd.Stdout or cmd.Stderr are nil, they are set
+// to the terminal (see UseTTY). The state of the terminal is saved and
+// the terminal is prepared for the command: canonical mode and echo are
+// enabled if the terminal is in raw mode, the alternate screen is left,
+// mouse tracking, bracketed paste, focus events and the keyboard protocol
+// (see EnableKeyboardEnhancement) are disabled and the cursor is shown
+// with the default shape. Afterwards the saved state is restored; the
+// program should redraw its screen if it used the alternate screen.
+// The active modes are queried from the terminal (see SetQueryTimeout).
+// While the command is running, SIGINT and SIGQUIT do not terminate
+// the program (RestoreOnExit ignores them, too).
+// It returns the error of cmd.Run.
+// It panics if stdin and stdout are not connected to a terminal.
+func RunCommand(cmd *exec.Cmd) error {
+	modes := queryModes(append([]int{25}, suspendModes...))
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	checkIsTerminal()
+	fd := int(inFile.Fd())
+	termios, err := unix.IoctlGetTermios(fd, termiosGet)
+	if err != nil {
+		return err
+	}
+	var suspend, resume strings.Builder
+	for _, m := range suspendModes {
+		if modes[m] {
+			fmt.Fprintf(&suspend, resetMode, m)
+			fmt.Fprintf(&resume, setMode, m)
+		}
+	}
+	outMu.Lock()
+	if cursorStyle != "" {
+		suspend.WriteString(cursorStyleSeq(CursorDefault, false))
+		resume.WriteString(cursorStyle)
+	}
+	outMu.Unlock()
+	if visible, ok := modes[25]; ok && !visible {
+		fmt.Fprintf(&suspend, setMode, 25)
+		fmt.Fprintf(&resume, resetMode, 25)
+	}
+	suspend.WriteString(keyboardOffSeq())
+	resume.WriteString(keyboardOnSeq())
+	writeOut(func() { out.WriteString(suspend.String()) })
+	if termios.Lflag&unix.ICANON == 0 {
+		cooked := *termios
+		cooked.Iflag |= unix.ICRNL | unix.IXON
+		cooked.Oflag |= unix.OPOST | unix.ONLCR
+		cooked.Lflag |= unix.ICANON | unix.ECHO | unix.ECHOE | unix.ISIG | unix.IEXTEN
+		unix.IoctlSetTermios(fd, termiosSet, &cooked)
+	}
+
+	if cmd.Stdin == nil {
+		cmd.Stdin = inFile
+	}
+	if cmd.Stdout == nil {
+		cmd.Stdout = outFile
+	}
+	if cmd.Stderr == nil {
+		cmd.Stderr = outFile
+	}
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, unix.SIGINT, unix.SIGQUIT)
+	atomic.StoreInt32(&runningCommand, 1)
+	err = cmd.Run()
+	atomic.StoreInt32(&runningCommand, 0)
+	signal.Stop(sigCh)
+
+	unix.IoctlSetTermios(fd, termiosSet, termios)
+	writeOut(func() { out.WriteString(resume.String()) })
+	return err
+}
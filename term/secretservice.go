@@ -0,0 +1,42 @@
+// +build dragonfly freebsd linux netbsd openbsd
+// +build keyring
+
+package term
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+)
+
+// SecretService is a CredentialStore that uses the Secret Service
+// of the desktop (e.g. GNOME Keyring or KWallet) with the program
+// secret-tool(1). The secrets are stored with the attributes service
+// and account. It is only built with the build tag keyring.
+type SecretService struct {
+	Label string // shown by the keyring manager (default: service)
+}
+
+// Get returns the secret or ErrCredentialNotFound.
+func (s SecretService) Get(service, account string) ([]byte, error) {
+	out, err := exec.Command("secret-tool", "lookup", "service", service, "account", account).Output()
+	var exitErr *exec.ExitError
+	if len(out) == 0 && (err == nil || errors.As(err, &exitErr)) {
+		return nil, ErrCredentialNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Set saves the secret; it is passed to secret-tool on stdin.
+func (s SecretService) Set(service, account string, secret []byte) error {
+	label := s.Label
+	if label == "" {
+		label = service
+	}
+	cmd := exec.Command("secret-tool", "store", "--label="+label, "service", service, "account", account)
+	cmd.Stdin = bytes.NewReader(secret)
+	return cmd.Run()
+}
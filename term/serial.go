@@ -0,0 +1,178 @@
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package term
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// Parity is the parity of a serial line.
+type Parity int
+
+// Parities.
+const (
+	ParityNone Parity = iota
+	ParityOdd
+	ParityEven
+)
+
+// SerialConfig contains the settings of a serial line for OpenSerial.
+// Serial lines can only be configured on Linux and BSD systems (including
+// macOS).
+type SerialConfig struct {
+	Baud     int    // baud rate; default: 9600
+	DataBits int    // 5 to 8; default: 8
+	Parity   Parity // default: ParityNone
+	StopBits int    // 1 or 2; default: 1
+	// RTS/CTS hardware flow control (software flow control is disabled,
+	// see SetFlowControl)
+	HardwareFlowControl bool
+}
+
+// modifyTermios gets the termios state of the terminal, calls f with it
+// and sets the modified state if f does not return an error.
+func modifyTermios(fd uintptr, f func(*unix.Termios) error) error {
+	termios, err := unix.IoctlGetTermios(int(fd), termiosGet)
+	if err != nil {
+		return err
+	}
+	if err = f(termios); err != nil {
+		return err
+	}
+	return unix.IoctlSetTermios(int(fd), termiosSet, termios)
+}
+
+// SetSpeed sets the input and output baud rate of the terminal (e.g. 115200).
+// It returns an error if the file descriptor fd is not connected to
+// a terminal or if the baud rate is not supported.
+func SetSpeed(fd uintptr, baud int) error {
+	return modifyTermios(fd, func(t *unix.Termios) error {
+		return setSpeed(t, baud)
+	})
+}
+
+// SetParity sets the parity of the terminal; parity checking of the input
+// is enabled for ParityOdd and ParityEven. It returns an error if the file
+// descriptor fd is not connected to a terminal.
+func SetParity(fd uintptr, parity Parity) error {
+	return modifyTermios(fd, func(t *unix.Termios) error {
+		return setParity(t, parity)
+	})
+}
+
+// SetStopBits sets the number of stop bits (1 or 2) of the terminal.
+// It returns an error if the file descriptor fd is not connected to
+// a terminal.
+func SetStopBits(fd uintptr, n int) error {
+	return modifyTermios(fd, func(t *unix.Termios) error {
+		return setStopBits(t, n)
+	})
+}
+
+func setParity(t *unix.Termios, parity Parity) error {
+	t.Cflag &^= unix.PARENB | unix.PARODD
+	t.Iflag &^= unix.INPCK
+	switch parity {
+	case ParityNone:
+	case ParityOdd:
+		t.Cflag |= unix.PARENB | unix.PARODD
+		t.Iflag |= unix.INPCK
+	case ParityEven:
+		t.Cflag |= unix.PARENB
+		t.Iflag |= unix.INPCK
+	default:
+		return fmt.Errorf("invalid parity: %d", parity)
+	}
+	return nil
+}
+
+func setStopBits(t *unix.Termios, n int) error {
+	switch n {
+	case 1:
+		t.Cflag &^= unix.CSTOPB
+	case 2:
+		t.Cflag |= unix.CSTOPB
+	default:
+		return fmt.Errorf("invalid number of stop bits: %d", n)
+	}
+	return nil
+}
+
+func setDataBits(t *unix.Termios, n int) error {
+	if n < 5 || n > 8 {
+		return fmt.Errorf("invalid number of data bits: %d", n)
+	}
+	t.Cflag &^= unix.CSIZE
+	switch n {
+	case 5:
+		t.Cflag |= unix.CS5
+	case 6:
+		t.Cflag |= unix.CS6
+	case 7:
+		t.Cflag |= unix.CS7
+	case 8:
+		t.Cflag |= unix.CS8
+	}
+	return nil
+}
+
+// OpenSerial opens the serial device with the given path (e.g.
+// "/dev/ttyUSB0") and configures it: raw mode (see MakeRaw), the settings
+// in config, modem control lines are ignored and the receiver is enabled.
+// The device does not become the controlling terminal. config may be nil.
+//   f, err := term.OpenSerial("/dev/ttyUSB0", &term.SerialConfig{Baud: 115200})
+//   if err != nil {
+//       panic(err)
+//   }
+//   defer f.Close()
+func OpenSerial(path string, config *SerialConfig) (*os.File, error) {
+	if config == nil {
+		config = &SerialConfig{}
+	}
+	// O_NONBLOCK: do not wait for the carrier detect signal
+	fd, err := unix.Open(path, unix.O_RDWR|unix.O_NOCTTY|unix.O_NONBLOCK|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: path, Err: err}
+	}
+	err = modifyTermios(uintptr(fd), func(t *unix.Termios) error {
+		t.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP |
+			unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON | unix.IXOFF
+		t.Oflag &^= unix.OPOST
+		t.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+		t.Cflag |= unix.CLOCAL | unix.CREAD
+		t.Cflag &^= unix.CRTSCTS
+		if config.HardwareFlowControl {
+			t.Cflag |= unix.CRTSCTS
+		}
+		t.Cc[unix.VMIN] = 1
+		t.Cc[unix.VTIME] = 0
+		baud, dataBits, stopBits := config.Baud, config.DataBits, config.StopBits
+		if baud == 0 {
+			baud = 9600
+		}
+		if dataBits == 0 {
+			dataBits = 8
+		}
+		if stopBits == 0 {
+			stopBits = 1
+		}
+		for _, err := range []error{setSpeed(t, baud), setDataBits(t, dataBits),
+			setParity(t, config.Parity), setStopBits(t, stopBits)} {
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		err = unix.SetNonblock(fd, false)
+	}
+	if err != nil {
+		unix.Close(fd)
+		return nil, err
+	}
+	return os.NewFile(uintptr(fd), path), nil
+}
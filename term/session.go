@@ -0,0 +1,72 @@
+// +build darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package term
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// SetCtty makes the terminal the file descriptor fd is connected to
+// the controlling terminal of the calling process, which must be a session
+// leader without a controlling terminal (see LoginTty).
+func SetCtty(fd uintptr) error {
+	return unix.IoctlSetInt(int(fd), unix.TIOCSCTTY, 0)
+}
+
+// LoginTty prepares the terminal the file descriptor fd is connected to
+// (usually the slave side of a pseudo terminal) for a login session like
+// login_tty(3): the calling process becomes the leader of a new session
+// with the terminal as its controlling terminal, fd is duplicated to
+// stdin, stdout and stderr and then closed. It fails if the calling process
+// is a process group leader. To run a command in a new session use
+// StartSession instead.
+func LoginTty(fd uintptr) error {
+	if _, err := unix.Setsid(); err != nil {
+		return err
+	}
+	if err := SetCtty(fd); err != nil {
+		return err
+	}
+	for i := 0; i <= 2; i++ {
+		if err := unix.Dup2(int(fd), i); err != nil {
+			return err
+		}
+	}
+	if fd > 2 {
+		return unix.Close(int(fd))
+	}
+	return nil
+}
+
+// StartSession starts the command in a new session with tty (usually
+// the slave side of a pseudo terminal) as its controlling terminal, e.g. to
+// run a shell in a terminal multiplexer. Stdin, Stdout and Stderr of
+// the command are set to tty if they are nil; Stdin must be tty.
+//   cmd := exec.Command("/bin/sh")
+//   if err := term.StartSession(cmd, tty); err != nil {
+//       return err
+//   }
+//   tty.Close() // the command has its own copy
+func StartSession(cmd *exec.Cmd, tty *os.File) error {
+	if cmd.Stdin == nil {
+		cmd.Stdin = tty
+	}
+	if cmd.Stdout == nil {
+		cmd.Stdout = tty
+	}
+	if cmd.Stderr == nil {
+		cmd.Stderr = tty
+	}
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	// Ctty is the file descriptor in the child (stdin)
+	cmd.SysProcAttr.Setsid = true
+	cmd.SysProcAttr.Setctty = true
+	cmd.SysProcAttr.Ctty = 0
+	return cmd.Start()
+}
@@ -0,0 +1,25 @@
+// +build aix zos
+
+package term
+
+import (
+	"os"
+	"os/exec"
+
+	"golang.org/x/sys/unix"
+)
+
+// SetCtty is not supported on this operating system.
+func SetCtty(fd uintptr) error {
+	return unix.ENOTSUP
+}
+
+// LoginTty is not supported on this operating system.
+func LoginTty(fd uintptr) error {
+	return unix.ENOTSUP
+}
+
+// StartSession is not supported on this operating system.
+func StartSession(cmd *exec.Cmd, tty *os.File) error {
+	return unix.ENOTSUP
+}
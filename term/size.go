@@ -0,0 +1,102 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+
+	"golang.org/x/sys/unix"
+)
+
+var (
+	sizeMu     sync.Mutex
+	sizeOnce   sync.Once
+	sizeValid  bool
+	sizeWidth  uint16
+	sizeHeight uint16
+	// sizeFile is outFile; it is set together with outFile (see
+	// setOutFile), so that Size does not need outMu, which may be locked
+	// by the caller.
+	sizeFile = os.Stdout
+)
+
+// Size returns the size (width, height) of the terminal connected to stdout
+// (or to the prompt output, see SetPromptOutput and UseTTY) like GetSize.
+// The size is cached; the cache is invalidated when the window size changes
+// (signal SIGWINCH). If refresh is true, the size is always queried from
+// the terminal. It returns an error if it is not connected to a terminal.
+func Size(refresh bool) (uint16, uint16, error) {
+	sizeOnce.Do(func() {
+		ch := make(chan os.Signal, 1)
+		signal.Notify(ch, unix.SIGWINCH)
+		go func() {
+			for range ch {
+				sizeMu.Lock()
+				sizeValid = false
+				sizeMu.Unlock()
+			}
+		}()
+	})
+	sizeMu.Lock()
+	defer sizeMu.Unlock()
+	if refresh || !sizeValid {
+		width, height, err := GetSize(sizeFile.Fd())
+		if err != nil {
+			sizeValid = false
+			return width, height, err
+		}
+		sizeWidth, sizeHeight, sizeValid = width, height, true
+	}
+	return sizeWidth, sizeHeight, nil
+}
+
+// InheritSize sets the size of the terminal the file descriptor to is
+// connected to (usually the master side of a pseudo terminal) to the size
+// of the terminal from is connected to (including the size in pixels).
+// The foreground process group of a pseudo terminal gets the signal
+// SIGWINCH if the size changes. It returns an error if from or to are not
+// connected to a terminal.
+func InheritSize(from, to uintptr) error {
+	ws, err := unix.IoctlGetWinsize(int(from), unix.TIOCGWINSZ)
+	if err != nil {
+		return err
+	}
+	return unix.IoctlSetWinsize(int(to), unix.TIOCSWINSZ, ws)
+}
+
+// SyncSize sets the size of the terminal to like InheritSize and again
+// each time the size of the terminal from changes (signal SIGWINCH) until
+// the returned function is called, e.g. for the lifetime of a child process
+// on a pseudo terminal:
+//   stop, err := term.SyncSize(os.Stdin.Fd(), ptmx.Fd())
+//   if err != nil {
+//       return err
+//   }
+//   defer stop()
+func SyncSize(from, to uintptr) (func(), error) {
+	if err := InheritSize(from, to); err != nil {
+		return nil, err
+	}
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, unix.SIGWINCH)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ch:
+				InheritSize(from, to)
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}, nil
+}
@@ -0,0 +1,20 @@
+// +build darwin
+
+package term
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// setSpeed sets the baud rate in the speed fields of the termios structure
+// (of type uint64 on these systems).
+func setSpeed(t *unix.Termios, baud int) error {
+	if baud <= 0 {
+		return fmt.Errorf("unsupported baud rate: %d", baud)
+	}
+	t.Ispeed = uint64(baud)
+	t.Ospeed = uint64(baud)
+	return nil
+}
@@ -0,0 +1,43 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StepIndicator shows the current step of a sequence of steps, e.g. of
+// a wizard ("Step 2 of 5: Network"). A Form shows it automatically
+// (see Form.Steps and Field.Step).
+type StepIndicator struct {
+	// show all titles ("Account > Network > Done") instead of the number
+	// and the title of the current step
+	Breadcrumb bool
+	Style      Style // style of the indicator (the current step in a breadcrumb)
+	// optional, replaces the default rendering
+	Func func(step int, titles []string) string
+}
+
+// breadcrumbSep separates the titles in a breadcrumb.
+const breadcrumbSep = " > "
+
+// Format returns the indicator for the step with the index step
+// of the steps with the titles.
+func (si *StepIndicator) Format(step int, titles []string) string {
+	if si.Func != nil {
+		return si.Func(step, titles)
+	}
+	if !si.Breadcrumb {
+		return si.Style.Render(fmt.Sprintf(msg(MsgStep), step+1, len(titles), titles[step]))
+	}
+	parts := make([]string, len(titles))
+	for i, t := range titles {
+		if i == step {
+			parts[i] = si.Style.Render(t)
+		} else {
+			parts[i] = theme.Muted.Render(t)
+		}
+	}
+	return strings.Join(parts, breadcrumbSep)
+}
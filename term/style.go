@@ -0,0 +1,135 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Color is a text color. The zero value is the default color of the terminal.
+type Color uint32
+
+const (
+	colorIndexed Color = 1 << 24
+	colorRGB     Color = 2 << 24
+)
+
+// The 8 standard colors; the bright variants are Index(8) to Index(15).
+const (
+	Black Color = colorIndexed + iota
+	Red
+	Green
+	Yellow
+	Blue
+	Magenta
+	Cyan
+	White
+)
+
+// Index returns the color with index n in the 256 color palette.
+func Index(n uint8) Color {
+	return colorIndexed + Color(n)
+}
+
+// RGB returns a true color.
+func RGB(r, g, b uint8) Color {
+	return colorRGB + Color(r)<<16 + Color(g)<<8 + Color(b)
+}
+
+// sgr returns the SGR parameters for the color;
+// base is 38 for the foreground and 48 for the background.
+func (c Color) sgr(base int) string {
+	switch c &^ 0xFFFFFF {
+	case colorIndexed:
+		n := int(c & 0xFF)
+		if n < 8 {
+			return strconv.Itoa(base - 8 + n)
+		}
+		return fmt.Sprintf("%d;5;%d", base, n)
+	case colorRGB:
+		return fmt.Sprintf("%d;2;%d;%d;%d", base, c>>16&0xFF, c>>8&0xFF, c&0xFF)
+	}
+	return ""
+}
+
+// Style is a combination of text attributes. The zero value
+// does not change the text.
+type Style struct {
+	Fg        Color
+	Bg        Color
+	Bold      bool
+	Faint     bool
+	Italic    bool
+	Underline bool
+	Reverse   bool
+}
+
+// ANSI escape codes: SGR ESC[<n>;...m
+func (s Style) seq() string {
+	var params []string
+	add := func(b bool, p string) {
+		if b {
+			params = append(params, p)
+		}
+	}
+	add(s.Bold, "1")
+	add(s.Faint, "2")
+	add(s.Italic, "3")
+	add(s.Underline, "4")
+	add(s.Reverse, "7")
+	add(s.Fg != 0, s.Fg.sgr(38))
+	add(s.Bg != 0, s.Bg.sgr(48))
+	if len(params) == 0 {
+		return ""
+	}
+	return "\x1b[" + strings.Join(params, ";") + "m"
+}
+
+// Sprint formats like fmt.Sprint and returns the result with the style
+// applied. The style is not applied if the environment variable NO_COLOR
+// is set (and not empty).
+func (s Style) Sprint(a ...interface{}) string {
+	return s.Render(fmt.Sprint(a...))
+}
+
+// Sprintf formats like fmt.Sprintf and returns the result with the style
+// applied (see Sprint).
+func (s Style) Sprintf(format string, a ...interface{}) string {
+	return s.Render(fmt.Sprintf(format, a...))
+}
+
+// Render returns str with the style applied (see Sprint).
+func (s Style) Render(str string) string {
+	seq := s.seq()
+	if seq == "" || str == "" || noColor() {
+		return str
+	}
+	return seq + str + "\x1b[0m"
+}
+
+// ANSI escape code: hyperlink (OSC 8;;<url> BEL <text> OSC 8;; BEL).
+const hyperlinkSeq = "\x1b]8;;%s\x07"
+
+// Hyperlink returns text as a hyperlink to url, which can be opened by
+// clicking on it. Terminals that do not support hyperlinks show only
+// the text.
+//   fmt.Println(term.Hyperlink("https://example.com", "Example"))
+func Hyperlink(url, text string) string {
+	return fmt.Sprintf(hyperlinkSeq, url) + text + fmt.Sprintf(hyperlinkSeq, "")
+}
+
+// StyledSegment is a part of a text with its style
+// (see InputOpt.Highlight).
+type StyledSegment struct {
+	Text  string
+	Style Style
+}
+
+// noColor returns whether the environment variable NO_COLOR is set to
+// a non-empty value (see https://no-color.org).
+func noColor() bool {
+	return os.Getenv("NO_COLOR") != ""
+}
@@ -0,0 +1,101 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+var (
+	// outMu must be locked when out is used.
+	outMu sync.Mutex
+	// promptMu serializes the input functions.
+	promptMu sync.Mutex
+)
+
+// writeOut calls f with outMu locked and flushes out afterwards.
+func writeOut(f func()) {
+	outMu.Lock()
+	defer outMu.Unlock()
+	f()
+	out.Flush()
+}
+
+type safeWriter struct {
+	w io.Writer
+}
+
+func (sw safeWriter) Write(p []byte) (int, error) {
+	return writeAbove(sw.w, p)
+}
+
+// writeAbove writes p to w. If an input function is running, the line
+// with the prompt is erased first and rendered again afterwards.
+func writeAbove(w io.Writer, p []byte) (int, error) {
+	outMu.Lock()
+	defer outMu.Unlock()
+	r := activeReader
+	if r != nil && r.bindings != nil {
+		out.WriteString(altScreenOff)
+	}
+	if r != nil {
+		cursorUp(out, r.cursorRow)
+		out.WriteString("\r\x1b[J")
+		r.cursorRow = 0
+	}
+	out.Flush()
+	n, err := w.Write(p)
+	if r != nil {
+		if len(p) > 0 && p[len(p)-1] != '\n' {
+			w.Write([]byte{'\n'})
+		}
+		r.render()
+		if r.bindings != nil {
+			out.WriteString(altScreenOn)
+			r.drawBindings()
+		}
+		out.Flush()
+	}
+	return n, err
+}
+
+// SafeWriter returns a writer that can be used for output to the terminal
+// (usually w is os.Stdout or os.Stderr) from other goroutines while an
+// input function of this package is running. Each write is serialized
+// with the output of the input functions, so that escape sequences are
+// never interleaved. The output is printed above the line with the
+// prompt and the input, which is rendered again afterwards (see Println).
+//
+// All input functions of this package are also serialized, i.e. if they
+// are called concurrently, they are run one after the other.
+func SafeWriter(w io.Writer) io.Writer {
+	return safeWriter{w}
+}
+
+// Fprintf formats according to a format specifier and writes to w.
+// If an input function is running, the output is printed above the prompt
+// and the prompt and the input typed so far are rendered again;
+// a missing final newline is added in that case.
+// It is safe to call it from other goroutines while an input function
+// is running.
+func Fprintf(w io.Writer, format string, a ...interface{}) (int, error) {
+	return writeAbove(w, []byte(fmt.Sprintf(format, a...)))
+}
+
+// Fprintln formats like fmt.Fprintln and writes to w (see Fprintf).
+func Fprintln(w io.Writer, a ...interface{}) (int, error) {
+	return writeAbove(w, []byte(fmt.Sprintln(a...)))
+}
+
+// Printf formats like fmt.Printf and writes to stdout (see Fprintf).
+func Printf(format string, a ...interface{}) (int, error) {
+	return Fprintf(os.Stdout, format, a...)
+}
+
+// Println formats like fmt.Println and writes to stdout (see Fprintf).
+func Println(a ...interface{}) (int, error) {
+	return Fprintln(os.Stdout, a...)
+}
@@ -0,0 +1,82 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/andreas19/go-term/term/ansi"
+)
+
+// ANSI escape codes: begin/end synchronized update (ESC[?2026h/l),
+//                    request DEC private mode (ESC[?<n>$p).
+const (
+	syncUpdateBegin = "\x1b[?2026h"
+	syncUpdateEnd   = "\x1b[?2026l"
+	requestMode     = "\x1b[?%d$p"
+)
+
+var syncUpdates bool
+
+// BeginSynchronizedUpdate tells the terminal to stop updating the screen
+// until EndSynchronizedUpdate is called, so that a redraw is shown at once
+// without tearing. Terminals that do not support it ignore it.
+func BeginSynchronizedUpdate() {
+	writeOut(func() { out.WriteString(syncUpdateBegin) })
+}
+
+// EndSynchronizedUpdate ends a synchronized update (see
+// BeginSynchronizedUpdate).
+func EndSynchronizedUpdate() {
+	writeOut(func() { out.WriteString(syncUpdateEnd) })
+}
+
+// SetSynchronizedUpdates sets whether the input functions wrap their
+// redraws in synchronized updates (default: false). It can be enabled if
+// SupportsSynchronizedUpdates returns true.
+func SetSynchronizedUpdates(b bool) {
+	syncUpdates = b
+}
+
+// SupportsSynchronizedUpdates queries the terminal whether it supports
+// synchronized updates (DEC private mode 2026). It panics if stdin and
+// stdout are not connected to a terminal.
+func SupportsSynchronizedUpdates() (bool, error) {
+	return queryMode(2026)
+}
+
+// queryMode returns whether the terminal supports the DEC private mode.
+// Terminals that do not know DECRQM only respond to DA1.
+func queryMode(mode int) (bool, error) {
+	resp, err := queryDA(fmt.Sprintf(requestMode, mode))
+	if err != nil {
+		return false, err
+	}
+	// response: ESC[?<mode>;<value>$y with value 0: not recognized,
+	// 1: set, 2: reset, 3: permanently set, 4: permanently reset
+	params, ok := parseCSI(resp, '?', 'y')
+	return ok && len(params) == 2 && params[0] == mode && params[1] != 0 && params[1] != 4, nil
+}
+
+// queryModes returns which of the DEC private modes are set; modes
+// the terminal does not report are missing.
+func queryModes(modes []int) map[int]bool {
+	var b strings.Builder
+	for _, m := range modes {
+		fmt.Fprintf(&b, requestMode, m)
+	}
+	resp, _ := queryDA(b.String())
+	set := map[int]bool{}
+	var p ansi.Parser
+	for _, seq := range p.Feed(resp) {
+		if seq.Type == ansi.CSI && seq.Prefix == '?' && seq.Final == 'y' {
+			// value 0: not recognized, 1/3: (permanently) set,
+			// 2/4: (permanently) reset
+			if v := seq.Param(1, 0); v != 0 {
+				set[seq.Param(0, 0)] = v == 1 || v == 3
+			}
+		}
+	}
+	return set
+}
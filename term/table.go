@@ -0,0 +1,140 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import "strings"
+
+// Align is the alignment of the cells of a column of a table.
+type Align uint8
+
+const (
+	AlignLeft Align = iota
+	AlignRight
+	AlignCenter
+)
+
+// TableOpt contains the options for the Table function.
+type TableOpt struct {
+	Align       []Align // alignment of the columns; default: AlignLeft
+	Width       int     // max. width; default: width of the terminal
+	HeaderStyle Style   // style of the header; default: Theme.Primary
+}
+
+// Table separators.
+const (
+	tableSep     = " │ "
+	tableRule    = "─"
+	tableRuleSep = "─┼─"
+)
+
+// Table returns the rows as a table with one line for each row (if it fits
+// into the width) and the header (if not nil) above a line. The cells may
+// contain escape sequences, e.g. text styled with Style. If the table is
+// wider than the width, the widest columns are narrowed and their cells
+// are wrapped (without styles). opt may be nil.
+//   fmt.Print(term.Table([]string{"Name", "Size"}, [][]string{
+//       {"a.txt", "12"},
+//       {"b.txt", "1024"},
+//   }, &term.TableOpt{Align: []term.Align{term.AlignLeft, term.AlignRight}}))
+//   ->
+//   Name  │ Size
+//   ──────┼─────
+//   a.txt │   12
+//   b.txt │ 1024
+func Table(header []string, rows [][]string, opt *TableOpt) string {
+	if opt == nil {
+		opt = &TableOpt{}
+	}
+	widths := tableWidths(header, rows, chartWidth(opt.Width))
+	headerStyle := opt.HeaderStyle
+	if headerStyle == (Style{}) {
+		headerStyle = theme.Primary
+	}
+	var b strings.Builder
+	if header != nil {
+		writeTableRow(&b, header, widths, opt.Align, headerStyle)
+		rules := make([]string, len(widths))
+		for i, w := range widths {
+			rules[i] = strings.Repeat(tableRule, w)
+		}
+		b.WriteString(strings.Join(rules, tableRuleSep) + "\n")
+	}
+	for _, row := range rows {
+		writeTableRow(&b, row, widths, opt.Align, Style{})
+	}
+	return b.String()
+}
+
+// tableWidths returns the widths of the columns; the widest columns are
+// narrowed until the table fits into the width.
+func tableWidths(header []string, rows [][]string, width int) []int {
+	var widths []int
+	for _, row := range append([][]string{header}, rows...) {
+		for i, cell := range row {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = maxInt(widths[i], textWidth(cell))
+		}
+	}
+	total := StringWidth(tableSep) * (len(widths) - 1)
+	for _, w := range widths {
+		total += w
+	}
+	for total > width {
+		widest := 0
+		for i, w := range widths {
+			if w > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= 1 {
+			break
+		}
+		widths[widest]--
+		total--
+	}
+	return widths
+}
+
+// writeTableRow writes the cells of a row; cells that are too wide
+// are wrapped.
+func writeTableRow(b *strings.Builder, row []string, widths []int, align []Align, style Style) {
+	cells := make([][]string, len(widths))
+	height := 1
+	for i, w := range widths {
+		var cell string
+		if i < len(row) {
+			cell = row[i]
+		}
+		if textWidth(cell) <= w {
+			cells[i] = []string{cell}
+		} else {
+			cells[i] = wrapText(stripEscapes(cell), w)
+		}
+		height = maxInt(height, len(cells[i]))
+	}
+	for l := 0; l < height; l++ {
+		parts := make([]string, len(widths))
+		for i, w := range widths {
+			var s string
+			if l < len(cells[i]) {
+				s = cells[i][l]
+			}
+			a := AlignLeft
+			if i < len(align) {
+				a = align[i]
+			}
+			pad := w - textWidth(s)
+			switch a {
+			case AlignRight:
+				parts[i] = strings.Repeat(" ", pad) + style.Render(s)
+			case AlignCenter:
+				parts[i] = strings.Repeat(" ", pad/2) + style.Render(s) + strings.Repeat(" ", pad-pad/2)
+			default:
+				parts[i] = style.Render(s) + strings.Repeat(" ", pad)
+			}
+		}
+		b.WriteString(strings.TrimRight(strings.Join(parts, tableSep), " ") + "\n")
+	}
+}
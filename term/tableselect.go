@@ -0,0 +1,226 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// TableSelect shows the rows as a table (see Table) and returns the index
+// of the row that is selected with the Up and Down keys (PgUp, PgDn, Home
+// and End move by pages or to the first or last row) and Enter. Typing
+// the number of a column (1-9) sorts the rows by it, typing it again
+// reverses the order; numbers are compared numerically. Typing / starts
+// a filter: only rows that contain the typed text (ignoring case) are shown
+// until Esc is typed; Enter ends typing the filter. If the table does not
+// fit into the terminal, the rows are scrolled. If Esc is typed and
+// SetCancelOnEsc is enabled, ErrCanceled is returned. opt may be nil.
+// It panics if stdin and stdout are not connected to a terminal or if
+// there are no rows.
+func TableSelect(header []string, rows [][]string, opt *TableOpt) (int, error) {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	checkIsTerminal()
+	if len(rows) == 0 {
+		panic("no rows")
+	}
+	if opt == nil {
+		opt = &TableOpt{}
+	}
+	t := &tableView{header: header, rows: rows, opt: opt}
+	// space for the arrow that marks the sort column
+	var arrowHeader []string
+	for _, h := range header {
+		arrowHeader = append(arrowHeader, h+sortArrow)
+	}
+	t.widths = tableWidths(arrowHeader, rows, chartWidth(opt.Width)-len(tableMarker))
+	t.update()
+	_, height := getTermSize()
+	if header == nil {
+		height += 2
+	}
+	t.height = maxInt(minInt(len(rows), height-4), 1)
+	err := runWidget(t.lines, t.handle)
+	if err != nil {
+		return -1, err
+	}
+	return t.order[t.selected], nil
+}
+
+// tableMarker marks the selected row of TableSelect, sortArrow and
+// sortArrowDesc the sort column.
+const (
+	tableMarker   = "> "
+	sortArrow     = " ▲"
+	sortArrowDesc = " ▼"
+)
+
+// tableView is the state of TableSelect.
+type tableView struct {
+	header    []string
+	rows      [][]string
+	opt       *TableOpt
+	widths    []int
+	order     []int // the indexes of the shown rows
+	selected  int   // index in order
+	top       int   // index in order of the first row on the screen
+	height    int   // number of rows on the screen
+	sortCol   int   // 1-based, 0: not sorted
+	sortDesc  bool
+	filter    []byte
+	filtering bool // the filter is typed
+}
+
+// update filters and sorts the rows.
+func (t *tableView) update() {
+	t.order = t.order[:0]
+	query := strings.ToLower(string(t.filter))
+	for i, row := range t.rows {
+		if query == "" || strings.Contains(strings.ToLower(stripEscapes(strings.Join(row, "\t"))), query) {
+			t.order = append(t.order, i)
+		}
+	}
+	if t.sortCol > 0 {
+		col := t.sortCol - 1
+		cell := func(i int) string {
+			if col < len(t.rows[i]) {
+				return stripEscapes(t.rows[i][col])
+			}
+			return ""
+		}
+		sort.SliceStable(t.order, func(i, j int) bool {
+			a, b := cell(t.order[i]), cell(t.order[j])
+			if t.sortDesc {
+				a, b = b, a
+			}
+			x, errX := strconv.ParseFloat(strings.TrimSpace(a), 64)
+			y, errY := strconv.ParseFloat(strings.TrimSpace(b), 64)
+			if errX == nil && errY == nil {
+				return x < y
+			}
+			return strings.ToLower(a) < strings.ToLower(b)
+		})
+	}
+	t.selected, t.top = 0, 0
+}
+
+// lines returns the lines of the table; the number of lines does not
+// change, so that shorter tables overwrite longer ones.
+func (t *tableView) lines() []string {
+	var lines []string
+	if t.header != nil {
+		header := make([]string, len(t.widths))
+		copy(header, t.header)
+		if t.sortCol > 0 && t.sortCol <= len(header) {
+			arrow := sortArrow
+			if t.sortDesc {
+				arrow = sortArrowDesc
+			}
+			header[t.sortCol-1] += arrow
+		}
+		var b strings.Builder
+		headerStyle := t.opt.HeaderStyle
+		if headerStyle == (Style{}) {
+			headerStyle = theme.Primary
+		}
+		writeTableRow(&b, header, t.widths, t.opt.Align, headerStyle)
+		rules := make([]string, len(t.widths))
+		for i, w := range t.widths {
+			rules[i] = strings.Repeat(tableRule, w)
+		}
+		b.WriteString(strings.Join(rules, tableRuleSep))
+		for _, l := range strings.Split(b.String(), "\n") {
+			lines = append(lines, strings.Repeat(" ", len(tableMarker))+l)
+		}
+	}
+	if t.selected < t.top {
+		t.top = t.selected
+	} else if t.selected >= t.top+t.height {
+		t.top = t.selected - t.height + 1
+	}
+	for i := t.top; i < t.top+t.height; i++ {
+		if i >= len(t.order) {
+			lines = append(lines, "")
+			continue
+		}
+		var b strings.Builder
+		marker, style := strings.Repeat(" ", len(tableMarker)), Style{}
+		if i == t.selected {
+			marker, style = theme.Accent.Render(tableMarker), theme.Accent
+		}
+		writeTableRow(&b, t.rows[t.order[i]], t.widths, t.opt.Align, style)
+		// only the first line of a row with wrapped cells is shown
+		lines = append(lines, marker+strings.SplitN(b.String(), "\n", 2)[0])
+	}
+	status := theme.Muted.Render(msg(MsgTableHelp))
+	if t.filtering || len(t.filter) > 0 {
+		status = fmt.Sprintf(msg(MsgTableFilter), t.filter)
+	}
+	return append(lines, status)
+}
+
+// handle processes a key.
+func (t *tableView) handle(key string) (bool, error) {
+	if t.filtering {
+		switch {
+		case key == "\r" || key == "\n":
+			t.filtering = false
+		case key == "\x1b":
+			t.filtering = false
+			t.filter = nil
+			t.update()
+		case key == "\x7f" || key == "\x08":
+			if len(t.filter) > 0 {
+				_, n := utf8.DecodeLastRune(t.filter)
+				t.filter = t.filter[:len(t.filter)-n]
+				t.update()
+			}
+		case key[0] >= ' ' && key[0] != 0x7F:
+			t.filter = append(t.filter, key...)
+			t.update()
+		}
+		return false, nil
+	}
+	switch key {
+	case "\x1b[A", "\x1bOA":
+		if t.selected > 0 {
+			t.selected--
+		}
+	case "\x1b[B", "\x1bOB":
+		if t.selected+1 < len(t.order) {
+			t.selected++
+		}
+	case "\x1b[5~":
+		t.selected = maxInt(t.selected-t.height, 0)
+	case "\x1b[6~":
+		t.selected = maxInt(minInt(t.selected+t.height, len(t.order)-1), 0)
+	case "\x1b[H", "\x1bOH", "\x1b[1~", "\x1b[7~":
+		t.selected = 0
+	case "\x1b[F", "\x1bOF", "\x1b[4~", "\x1b[8~":
+		t.selected = maxInt(len(t.order)-1, 0)
+	case "/":
+		t.filtering = true
+	case "\x1b":
+		if len(t.filter) > 0 {
+			t.filter = nil
+			t.update()
+			return false, nil
+		}
+	case "\r", "\n":
+		if len(t.order) == 0 {
+			return false, nil
+		}
+	default:
+		if len(key) == 1 && key[0] >= '1' && key[0] <= '9' && int(key[0]-'0') <= len(t.widths) {
+			col := int(key[0] - '0')
+			t.sortDesc = col == t.sortCol && !t.sortDesc
+			t.sortCol = col
+			t.update()
+		}
+	}
+	return widgetDone(key)
+}
@@ -0,0 +1,40 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import "fmt"
+
+// Queue selects the data that is discarded by Flush.
+type Queue int
+
+// Queues.
+const (
+	InputQueue  Queue = iota // received but not read data
+	OutputQueue              // written but not transmitted data
+	BothQueues
+)
+
+// SendBreak sends a break condition (a stream of zero bits for 0.25 to
+// 0.5 seconds) if the file descriptor fd is connected to a serial line.
+// It returns an error if fd is not connected to a terminal.
+func SendBreak(fd uintptr) error {
+	return sendBreak(int(fd))
+}
+
+// Drain waits until all output written to the file descriptor fd has been
+// transmitted. It returns an error if fd is not connected to a terminal.
+func Drain(fd uintptr) error {
+	return drain(int(fd))
+}
+
+// Flush discards the data in the given queue of the terminal, e.g. keys
+// that were typed ahead before a prompt:
+//   term.Flush(os.Stdin.Fd(), term.InputQueue)
+// It returns an error if the file descriptor fd is not connected to
+// a terminal.
+func Flush(fd uintptr, queue Queue) error {
+	if queue < InputQueue || queue > BothQueues {
+		return fmt.Errorf("invalid queue: %d", queue)
+	}
+	return flush(int(fd), queue)
+}
@@ -0,0 +1,18 @@
+// +build aix linux solaris
+
+package term
+
+import "golang.org/x/sys/unix"
+
+func sendBreak(fd int) error {
+	return unix.IoctlSetInt(fd, unix.TCSBRK, 0)
+}
+
+func drain(fd int) error {
+	// TCSBRK with a non-zero argument only waits for the output
+	return unix.IoctlSetInt(fd, unix.TCSBRK, 1)
+}
+
+func flush(fd int, queue Queue) error {
+	return unix.IoctlSetInt(fd, unix.TCFLSH, []int{unix.TCIFLUSH, unix.TCOFLUSH, unix.TCIOFLUSH}[queue])
+}
@@ -0,0 +1,28 @@
+// +build darwin dragonfly freebsd netbsd openbsd
+
+package term
+
+import (
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+func sendBreak(fd int) error {
+	if err := unix.IoctlSetInt(fd, unix.TIOCSBRK, 0); err != nil {
+		return err
+	}
+	time.Sleep(400 * time.Millisecond)
+	return unix.IoctlSetInt(fd, unix.TIOCCBRK, 0)
+}
+
+func drain(fd int) error {
+	return unix.IoctlSetInt(fd, unix.TIOCDRAIN, 0)
+}
+
+func flush(fd int, queue Queue) error {
+	// the argument is a pointer to FREAD and/or FWRITE
+	// which have the same values as TCIFLUSH and TCOFLUSH
+	which := []int{unix.TCIFLUSH, unix.TCOFLUSH, unix.TCIOFLUSH}[queue]
+	return unix.IoctlSetPointerInt(fd, unix.TIOCFLUSH, which)
+}
@@ -0,0 +1,30 @@
+// +build zos
+
+package term
+
+import (
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+func sendBreak(fd int) error {
+	if err := unix.IoctlSetInt(fd, unix.TIOCSBRK, 0); err != nil {
+		return err
+	}
+	time.Sleep(400 * time.Millisecond)
+	return unix.IoctlSetInt(fd, unix.TIOCCBRK, 0)
+}
+
+func drain(fd int) error {
+	// setting the attributes with TCSETSW waits for the output
+	termios, err := unix.IoctlGetTermios(fd, termiosGet)
+	if err != nil {
+		return err
+	}
+	return unix.IoctlSetTermios(fd, unix.TCSETSW, termios)
+}
+
+func flush(fd int, queue Queue) error {
+	return unix.ENOTSUP
+}
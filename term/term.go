@@ -3,13 +3,19 @@
 package term
 
 import (
+	"bufio"
+	"errors"
+	"fmt"
 	"os"
 	"strings"
-	"unicode/utf8"
 
 	"golang.org/x/sys/unix"
 )
 
+// out buffers all output to the terminal to avoid flicker and
+// unnecessary system calls. It must be flushed after each update.
+var out = bufio.NewWriter(outWriter())
+
 // GetSize returns the size (width, height) of the terminal. It returns
 // an error if the file descriptor fd is not connected to a terminal.
 func GetSize(fd uintptr) (uint16, uint16, error) {
@@ -17,6 +23,26 @@ func GetSize(fd uintptr) (uint16, uint16, error) {
 	return sz.Col, sz.Row, err
 }
 
+// GetPixelSize returns the size (width, height) of the terminal in pixels.
+// The values are 0 if the terminal does not report them. It returns
+// an error if the file descriptor fd is not connected to a terminal.
+func GetPixelSize(fd uintptr) (uint16, uint16, error) {
+	sz, err := unix.IoctlGetWinsize(int(fd), unix.TIOCGWINSZ)
+	return sz.Xpixel, sz.Ypixel, err
+}
+
+// CellSize returns the size (width, height) of one character cell of
+// the terminal in pixels. The values are 0 if the terminal does not report
+// its size in pixels. It returns an error if the file descriptor fd is not
+// connected to a terminal.
+func CellSize(fd uintptr) (uint16, uint16, error) {
+	sz, err := unix.IoctlGetWinsize(int(fd), unix.TIOCGWINSZ)
+	if err != nil || sz.Col == 0 || sz.Row == 0 {
+		return 0, 0, err
+	}
+	return sz.Xpixel / sz.Col, sz.Ypixel / sz.Row, nil
+}
+
 // IsTerminal returns whether the file descriptor fd is connected to a terminal.
 func IsTerminal(fd uintptr) bool {
 	_, err := unix.IoctlGetTermios(int(fd), termiosGet)
@@ -63,7 +89,7 @@ func MakeRaw(fd uintptr) (func() error, error) {
 }
 
 func center(s string, w int) string {
-	strLen := utf8.RuneCountInString(s)
+	strLen := StringWidth(s)
 	if strLen >= w {
 		return s
 	}
@@ -79,8 +105,65 @@ func maxInt(x, y int) int {
 	return y
 }
 
+func minInt(x, y int) int {
+	if x < y {
+		return x
+	}
+	return y
+}
+
+// TerminalCheck selects which streams the input functions require to be
+// connected to a terminal.
+type TerminalCheck int
+
+// Terminal checks.
+const (
+	CheckInput  TerminalCheck = 1 << iota // stdin
+	CheckOutput                           // stdout (the stream the prompts are written to)
+	CheckNone   TerminalCheck = 0
+	CheckBoth                 = CheckInput | CheckOutput
+)
+
+var terminalCheck = CheckBoth
+
+// SetTerminalCheck sets which streams the input functions require to be
+// connected to a terminal (default: CheckBoth). With CheckInput the user can
+// still be prompted if the output is redirected to a file or a pipe
+// (the prompt and the echoed input are written to it).
+func SetTerminalCheck(check TerminalCheck) {
+	terminalCheck = check
+}
+
+// streamName returns the name of f for error messages.
+func streamName(f *os.File) string {
+	switch f {
+	case os.Stdin:
+		return "STDIN"
+	case os.Stdout:
+		return "STDOUT"
+	case os.Stderr:
+		return "STDERR"
+	}
+	return f.Name()
+}
+
+// ErrNotATerminal is returned if a file is not connected to a terminal.
+// The functions that panic if stdin or stdout are not connected to
+// a terminal panic with an error wrapping it.
+var ErrNotATerminal = errors.New("not connected to a terminal")
+
+// checkIsTerminal panics if one of the streams selected with
+// SetTerminalCheck is not connected to a terminal.
 func checkIsTerminal() {
-	if !(IsTerminal(os.Stdin.Fd()) && IsTerminal(os.Stdout.Fd())) {
-		panic("STDIN and STDOUT must be connected to a terminal")
+	in := terminalCheck&CheckInput == 0 || IsTerminal(inFile.Fd())
+	echo := echoOutput()
+	output := terminalCheck&CheckOutput == 0 || IsTerminal(echo.Fd())
+	switch {
+	case !in && !output:
+		panic(fmt.Errorf("%s and %s %w", streamName(inFile), streamName(echo), ErrNotATerminal))
+	case !in:
+		panic(fmt.Errorf("%s %w", streamName(inFile), ErrNotATerminal))
+	case !output:
+		panic(fmt.Errorf("%s %w", streamName(echo), ErrNotATerminal))
 	}
 }
@@ -0,0 +1,262 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Theme maps semantic roles to the styles that are used by the components
+// of this package (see SetTheme).
+type Theme struct {
+	Primary Style // the default option of a menu
+	Accent  Style // the selected option of a radio group, the bar of a slider
+	Success Style // added lines of Diff
+	Warning Style // for use by programs
+	Error   Style // deleted lines of Diff, the error of an invalid input
+	Muted   Style // placeholders, hints, inactive steps
+}
+
+// DefaultTheme uses the standard colors, which the terminal adapts
+// to dark and light backgrounds.
+var DefaultTheme = Theme{
+	Primary: Style{Bold: true},
+	Accent:  Style{Fg: Cyan},
+	Success: Style{Fg: Green},
+	Warning: Style{Fg: Yellow},
+	Error:   Style{Fg: Red},
+	Muted:   Style{Faint: true},
+}
+
+var theme = DefaultTheme
+
+// SetTheme sets the theme of all components; it takes effect
+// the next time a component is shown.
+func SetTheme(t Theme) {
+	theme = t
+}
+
+// CurrentTheme returns the theme set with SetTheme.
+func CurrentTheme() Theme {
+	return theme
+}
+
+// SetThemeFor sets the theme dark or light depending on the background
+// of the terminal (see DarkBackground). The error of DarkBackground is
+// returned; the dark theme is set in that case.
+//   dark, _ := term.LoadTheme("dark.toml")
+//   light, _ := term.LoadTheme("light.toml")
+//   term.SetThemeFor(dark, light)
+func SetThemeFor(dark, light Theme) error {
+	isDark, err := DarkBackground()
+	if isDark {
+		SetTheme(dark)
+	} else {
+		SetTheme(light)
+	}
+	return err
+}
+
+// DarkBackground reports whether the background of the terminal is dark.
+// It is queried from the terminal (see QueryBackground); if the terminal
+// does not respond, the environment variable COLORFGBG is used (set
+// by some terminals, e.g. "15;0"). It returns true and the error of
+// the query if neither is available.
+// It panics if stdin and stdout are not connected to a terminal.
+func DarkBackground() (bool, error) {
+	r, g, b, err := QueryBackground()
+	if err == nil {
+		// relative luminance (ITU-R BT.601)
+		return 299*int(r)+587*int(g)+114*int(b) < 128*1000, nil
+	}
+	if v := os.Getenv("COLORFGBG"); v != "" {
+		parts := strings.Split(v, ";")
+		if n, err := strconv.Atoi(parts[len(parts)-1]); err == nil {
+			return n < 7 || n == 8, nil
+		}
+	}
+	return true, err
+}
+
+// ErrThemeFormat is returned by LoadTheme if the file is invalid.
+var ErrThemeFormat = errors.New("invalid theme")
+
+// LoadTheme reads a theme from a JSON file or from a TOML file (if the name
+// ends with .toml). The styles of roles that are missing are taken from
+// DefaultTheme. The roles are the fields of Theme in lower case, the styles
+// have the keys fg, bg, bold, faint, italic, underline and reverse. Colors
+// are names (e.g. "red" or "bright-red"), numbers of the 256 color palette
+// or true colors ("#rrggbb").
+//   # TOML
+//   [primary]
+//   fg = "#5f87ff"
+//   bold = true
+//
+//   // JSON
+//   {"primary": {"fg": "#5f87ff", "bold": true}}
+// Only this subset of TOML is supported.
+func LoadTheme(name string) (Theme, error) {
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return Theme{}, err
+	}
+	var roles map[string]map[string]interface{}
+	if strings.EqualFold(filepath.Ext(name), ".toml") {
+		roles, err = parseThemeTOML(string(data))
+	} else if err = json.Unmarshal(data, &roles); err != nil {
+		err = fmt.Errorf("%w: %v", ErrThemeFormat, err)
+	}
+	if err != nil {
+		return Theme{}, err
+	}
+	t := DefaultTheme
+	fields := map[string]*Style{
+		"primary": &t.Primary,
+		"accent":  &t.Accent,
+		"success": &t.Success,
+		"warning": &t.Warning,
+		"error":   &t.Error,
+		"muted":   &t.Muted,
+	}
+	for role, attrs := range roles {
+		style, ok := fields[role]
+		if !ok {
+			return Theme{}, fmt.Errorf("%w: unknown role %q", ErrThemeFormat, role)
+		}
+		if *style, err = parseThemeStyle(attrs); err != nil {
+			return Theme{}, fmt.Errorf("%w: %s: %v", ErrThemeFormat, role, err)
+		}
+	}
+	return t, nil
+}
+
+// parseThemeTOML parses tables with keys and string, boolean or integer
+// values.
+func parseThemeTOML(data string) (map[string]map[string]interface{}, error) {
+	roles := map[string]map[string]interface{}{}
+	var table map[string]interface{}
+	for i, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(stripComment(line))
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			table = map[string]interface{}{}
+			roles[strings.TrimSpace(line[1:len(line)-1])] = table
+			continue
+		}
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 || table == nil {
+			return nil, fmt.Errorf("%w: line %d", ErrThemeFormat, i+1)
+		}
+		key, val := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		switch {
+		case val == "true", val == "false":
+			table[key] = val == "true"
+		case strings.HasPrefix(val, `"`):
+			s, err := strconv.Unquote(val)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d", ErrThemeFormat, i+1)
+			}
+			table[key] = s
+		default:
+			n, err := strconv.Atoi(val)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d", ErrThemeFormat, i+1)
+			}
+			table[key] = float64(n) // like encoding/json
+		}
+	}
+	return roles, nil
+}
+
+// stripComment removes a comment (# outside of a string) from a line.
+func stripComment(line string) string {
+	inString := false
+	for i, ch := range line {
+		switch {
+		case ch == '"' && (i == 0 || line[i-1] != '\\'):
+			inString = !inString
+		case ch == '#' && !inString:
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// parseThemeStyle returns the style with the attributes.
+func parseThemeStyle(attrs map[string]interface{}) (Style, error) {
+	var style Style
+	flags := map[string]*bool{
+		"bold":      &style.Bold,
+		"faint":     &style.Faint,
+		"italic":    &style.Italic,
+		"underline": &style.Underline,
+		"reverse":   &style.Reverse,
+	}
+	for key, val := range attrs {
+		var err error
+		switch key {
+		case "fg":
+			style.Fg, err = parseThemeColor(val)
+		case "bg":
+			style.Bg, err = parseThemeColor(val)
+		default:
+			flag, ok := flags[key]
+			if !ok {
+				return style, fmt.Errorf("unknown key %q", key)
+			}
+			if *flag, ok = val.(bool); !ok {
+				err = fmt.Errorf("invalid value for %s: %v", key, val)
+			}
+		}
+		if err != nil {
+			return style, err
+		}
+	}
+	return style, nil
+}
+
+// colorNames are the names of the 8 standard colors.
+var colorNames = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// parseThemeColor returns the color for a name, a number of the palette
+// or #rrggbb.
+func parseThemeColor(val interface{}) (Color, error) {
+	if n, ok := val.(float64); ok {
+		if n < 0 || n > 255 || n != float64(int(n)) {
+			return 0, fmt.Errorf("invalid color: %v", val)
+		}
+		return Index(uint8(n)), nil
+	}
+	s, ok := val.(string)
+	if !ok {
+		return 0, fmt.Errorf("invalid color: %v", val)
+	}
+	s = strings.ToLower(s)
+	if strings.HasPrefix(s, "#") && len(s) == 7 {
+		if v, err := strconv.ParseUint(s[1:], 16, 32); err == nil {
+			return RGB(uint8(v>>16), uint8(v>>8), uint8(v)), nil
+		}
+	}
+	if n, err := strconv.ParseUint(s, 10, 8); err == nil {
+		return Index(uint8(n)), nil
+	}
+	offset := 0
+	if strings.HasPrefix(s, "bright-") {
+		s, offset = s[len("bright-"):], 8
+	}
+	for i, name := range colorNames {
+		if s == name {
+			return Index(uint8(i + offset)), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid color: %q", val)
+}
@@ -0,0 +1,38 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"io"
+	"log"
+	"strings"
+)
+
+var transcript *log.Logger
+
+// SetTranscript starts writing a transcript of all prompts and the entered
+// values to w (nil stops it). Each line is prefixed with date and time.
+// Values that were not echoed (e.g. passwords) are replaced by "(hidden)";
+// if the default value was used, "(default)" is written.
+// The transcript includes the prompts of the functions that support the
+// non-interactive mode (see function SetAnswers).
+func SetTranscript(w io.Writer) {
+	if w == nil {
+		transcript = nil
+	} else {
+		transcript = log.New(w, "", log.LstdFlags)
+	}
+}
+
+func logTranscript(prompt string, opt *InputOpt, s string) {
+	if transcript == nil {
+		return
+	}
+	switch {
+	case s == "":
+		s = "(default)"
+	case opt.Echo != EchoNormal:
+		s = "(hidden)"
+	}
+	transcript.Printf("%s %s", strings.TrimRight(prompt, " "), s)
+}
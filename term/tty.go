@@ -0,0 +1,107 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import "os"
+
+var (
+	// inFile and outFile are the files used by the input functions;
+	// outMu must be locked when they are changed.
+	inFile  = os.Stdin
+	outFile = os.Stdout
+	// promptFile is the file set with SetPromptOutput.
+	promptFile = os.Stdout
+	// tty is the controlling terminal if it was opened by UseTTY.
+	tty *os.File
+	// echoFile is the file set with SetEchoOutput (nil: outFile).
+	echoFile *os.File
+)
+
+// SetPromptOutput sets the file the input functions write the prompts and
+// the echoed input to (default: os.Stdout). Writing them to os.Stderr keeps
+// stdout free for the output of the program:
+//   term.SetPromptOutput(os.Stderr)
+//   mytool > out.json
+// It is ignored while the controlling terminal is used (see UseTTY).
+func SetPromptOutput(f *os.File) {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	outMu.Lock()
+	defer outMu.Unlock()
+	promptFile = f
+	if inFile != tty {
+		setOutFile(f)
+	}
+}
+
+// setOutFile sets the file for the output of the input functions;
+// outMu must be locked.
+func setOutFile(f *os.File) {
+	out.Flush()
+	outFile = f
+	out.Reset(outWriter())
+	sizeMu.Lock()
+	sizeFile = f
+	sizeValid = false
+	sizeMu.Unlock()
+}
+
+// SetEchoOutput sets the file the line editor of the input functions
+// (e.g. Input, GetBytes and GetPassword) writes the echoed input to, while
+// the prompt is still written to the prompt output (see SetPromptOutput).
+// This way the input can be edited on the terminal even if stdout is
+// redirected:
+//   tty, _ := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+//   term.SetEchoOutput(tty)
+//   mytool > answers.txt
+// If the prompt output is a terminal too, it is assumed to be the same
+// screen and the echoed input is shown after the prompt; otherwise it is
+// shown at the start of the line and ends with a newline.
+// Menus and widgets still write to the prompt output. nil resets it.
+// It is ignored while the controlling terminal is used (see UseTTY).
+func SetEchoOutput(f *os.File) {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	outMu.Lock()
+	defer outMu.Unlock()
+	echoFile = f
+}
+
+// echoOutput returns the file the line editor writes to;
+// outMu must be locked.
+func echoOutput() *os.File {
+	if echoFile == nil || inFile == tty {
+		return outFile
+	}
+	return echoFile
+}
+
+// UseTTY sets whether the input functions read from and write to
+// the controlling terminal (/dev/tty) instead of stdin and stdout, so that
+// a program can prompt the user even if stdin or stdout are redirected:
+//   mytool | tee log
+// Output functions like Println still write to stdout.
+// It returns an error if the controlling terminal cannot be opened.
+func UseTTY(b bool) error {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	f := os.Stdin
+	if b {
+		if tty == nil {
+			var err error
+			if tty, err = os.OpenFile("/dev/tty", os.O_RDWR, 0); err != nil {
+				return err
+			}
+		}
+		f = tty
+	}
+	outMu.Lock()
+	defer outMu.Unlock()
+	inFile = f
+	if b {
+		setOutFile(f)
+	} else {
+		setOutFile(promptFile)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"os"
+	"os/user"
+	"strconv"
+
+	"golang.org/x/sys/unix"
+)
+
+// TTYPerm is the owner and the permissions of a terminal device.
+type TTYPerm struct {
+	UID  int
+	GID  int
+	Mode os.FileMode // permission bits
+}
+
+// GetTTYPerm returns the owner and the permissions of the terminal
+// the file descriptor fd is connected to. It returns an error if fd is
+// not connected to a terminal.
+func GetTTYPerm(fd uintptr) (TTYPerm, error) {
+	if !IsTerminal(fd) {
+		return TTYPerm{}, ErrNotATerminal
+	}
+	var st unix.Stat_t
+	if err := unix.Fstat(int(fd), &st); err != nil {
+		return TTYPerm{}, err
+	}
+	return TTYPerm{int(st.Uid), int(st.Gid), os.FileMode(st.Mode) & os.ModePerm}, nil
+}
+
+// SetTTYPerm sets the owner and the permissions of the terminal the file
+// descriptor fd is connected to; this usually requires root privileges.
+// It returns an error if fd is not connected to a terminal.
+func SetTTYPerm(fd uintptr, perm TTYPerm) error {
+	if !IsTerminal(fd) {
+		return ErrNotATerminal
+	}
+	if err := unix.Fchown(int(fd), perm.UID, perm.GID); err != nil {
+		return err
+	}
+	return unix.Fchmod(int(fd), uint32(perm.Mode&os.ModePerm))
+}
+
+// Secure returns whether the terminal is owned by the user with the ID uid
+// and cannot be read or written by others; only the group may write to it
+// (e.g. the group tty for write(1)).
+func (p TTYPerm) Secure(uid int) bool {
+	return p.UID == uid && p.Mode&0057 == 0
+}
+
+// SecureTTY gives the terminal the file descriptor fd is connected to to
+// the user with the ID uid like login programs do: the group is set to
+// tty and the mode to 0620 (or the group is not changed and the mode is set
+// to 0600 if there is no group tty). This usually requires root privileges.
+//   if err := term.SecureTTY(os.Stdin.Fd(), uid); err != nil {
+//       return err
+//   }
+func SecureTTY(fd uintptr, uid int) error {
+	perm, err := GetTTYPerm(fd)
+	if err != nil {
+		return err
+	}
+	perm.UID, perm.Mode = uid, 0600
+	if g, err := user.LookupGroup("tty"); err == nil {
+		if gid, err := strconv.Atoi(g.Gid); err == nil {
+			perm.GID, perm.Mode = gid, 0620
+		}
+	}
+	return SetTTYPerm(fd, perm)
+}
+
+// Disconnect detaches the calling process from its controlling terminal,
+// which the file descriptor fd must be connected to. If the process is
+// the session leader, the foreground process group of the terminal gets
+// the signals SIGHUP and SIGCONT and all processes in the session lose
+// the terminal.
+func Disconnect(fd uintptr) error {
+	return unix.IoctlSetInt(int(fd), unix.TIOCNOTTY, 0)
+}
+
+// VHangup simulates a hangup of the controlling terminal of the calling
+// process: other processes that have it open can no longer use it. Login
+// programs call it before the terminal is given to a new user. It requires
+// root privileges (CAP_SYS_TTY_CONFIG) and returns unix.ENOTSUP if
+// the operating system does not support it (only Linux does).
+func VHangup() error {
+	return vhangup()
+}
@@ -0,0 +1,61 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"encoding/binary"
+	"io"
+	"time"
+)
+
+// A ttyrec file is a sequence of frames with the output to the terminal.
+// Each frame has a header with the time (seconds and microseconds since
+// the epoch) and the length of the data; all values are little-endian
+// 32-bit integers.
+type ttyrecHeader struct {
+	Sec  uint32
+	Usec uint32
+	Len  uint32
+}
+
+// NewTTYRecRecorder returns a Recorder that writes the output to w in
+// the ttyrec format, which does not contain input and resize events.
+func NewTTYRecRecorder(w io.Writer) *Recorder {
+	return &Recorder{w: w, start: time.Now(), ttyrec: true}
+}
+
+func writeTTYRecFrame(w io.Writer, t time.Time, data string) error {
+	hdr := ttyrecHeader{uint32(t.Unix()), uint32(t.Nanosecond() / 1000), uint32(len(data))}
+	if err := binary.Write(w, binary.LittleEndian, hdr); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, data)
+	return err
+}
+
+// NewTTYRecPlayer reads a recording in the ttyrec format from r.
+// The size of the terminal is unknown for this format.
+func NewTTYRecPlayer(r io.Reader) (*Player, error) {
+	p := &Player{}
+	var first float64
+	for {
+		var hdr ttyrecHeader
+		if err := binary.Read(r, binary.LittleEndian, &hdr); err == io.EOF {
+			return p, nil
+		} else if err != nil {
+			return nil, err
+		}
+		data := make([]byte, hdr.Len)
+		if _, err := io.ReadFull(r, data); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		t := float64(hdr.Sec) + float64(hdr.Usec)/1e6
+		if len(p.events) == 0 {
+			first = t
+		}
+		p.events = append(p.events, castEvent{t - first, "o", string(data)})
+	}
+}
@@ -0,0 +1,124 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var siFactors = map[string]int64{
+	"":  1,
+	"k": 1e3,
+	"m": 1e6,
+	"g": 1e9,
+	"t": 1e12,
+	"p": 1e15,
+	"e": 1e18,
+}
+
+var binaryFactors = map[string]int64{
+	"ki": 1 << 10,
+	"mi": 1 << 20,
+	"gi": 1 << 30,
+	"ti": 1 << 40,
+	"pi": 1 << 50,
+	"ei": 1 << 60,
+}
+
+// ParseBytes parses a byte size with an optional unit: B, kB, MB, GB, TB,
+// PB and EB (powers of 1000) or KiB, MiB, GiB, TiB, PiB and EiB (powers
+// of 1024); the final B may be omitted and the case of the units is ignored.
+// The number may have a fraction with '.' or ',' as decimal separator.
+//   "512" -> 512, "10MB" -> 10000000, "1.5 GiB" -> 1610612736, "2,5k" -> 2500
+func ParseBytes(s string) (int64, error) {
+	num, unit := splitUnit(s)
+	unit = strings.TrimSuffix(strings.ToLower(unit), "b")
+	factor, ok := siFactors[unit]
+	if !ok {
+		if factor, ok = binaryFactors[unit]; !ok {
+			return 0, fmt.Errorf("invalid byte size: %q", s)
+		}
+	}
+	return scaleNumber(s, num, factor)
+}
+
+// ParseCount parses a count with an optional SI suffix:
+// k, M, G, T, P or E (the case is ignored).
+// The number may have a fraction with '.' or ',' as decimal separator.
+//   "250" -> 250, "1.5k" -> 1500, "2M" -> 2000000
+func ParseCount(s string) (int64, error) {
+	num, unit := splitUnit(s)
+	factor, ok := siFactors[strings.ToLower(unit)]
+	if !ok {
+		return 0, fmt.Errorf("invalid count: %q", s)
+	}
+	return scaleNumber(s, num, factor)
+}
+
+// BytesConv can be used as InputOpt.ConvFunc for values of type int64
+// (see ParseBytes).
+func BytesConv(s string) (interface{}, error) {
+	return ParseBytes(s)
+}
+
+// CountConv can be used as InputOpt.ConvFunc for values of type int64
+// (see ParseCount).
+func CountConv(s string) (interface{}, error) {
+	return ParseCount(s)
+}
+
+// InputBytes gets a byte size from a terminal (see ParseBytes).
+// The prompt is shown again until a valid size is entered.
+// It panics if stdin and stdout are not connected to a terminal (unless
+// in non-interactive mode).
+func InputBytes(prompt string) (int64, error) {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	var n int64
+	err := inputAny(prompt, &n, &InputOpt{ConvFunc: BytesConv})
+	return n, err
+}
+
+// splitUnit splits s into the number and the unit.
+func splitUnit(s string) (string, string) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return !unicode.IsDigit(r) && r != '.' && r != ',' && r != '+' && r != '-'
+	})
+	if i < 0 {
+		return s, ""
+	}
+	return strings.TrimSpace(s[:i]), strings.TrimSpace(s[i:])
+}
+
+// scaleNumber returns num * factor; s is used in error messages.
+func scaleNumber(s, num string, factor int64) (int64, error) {
+	if strings.Count(num, ",") == 1 && !strings.Contains(num, ".") {
+		num = strings.Replace(num, ",", ".", 1)
+	}
+	if !strings.Contains(num, ".") {
+		n, err := strconv.ParseInt(num, 10, 64)
+		if err == nil && (n > math.MaxInt64/factor || n < math.MinInt64/factor) {
+			err = strconv.ErrRange
+		}
+		if err != nil {
+			return 0, fmt.Errorf("invalid number: %q", s)
+		}
+		return n * factor, nil
+	}
+	f, err := strconv.ParseFloat(num, 64)
+	if err == nil {
+		f = math.Round(f * float64(factor))
+		if f >= math.MaxInt64 || f < math.MinInt64 {
+			err = strconv.ErrRange
+		}
+	}
+	if err != nil {
+		return 0, fmt.Errorf("invalid number: %q", s)
+	}
+	return int64(f), nil
+}
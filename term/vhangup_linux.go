@@ -0,0 +1,12 @@
+// +build linux
+
+package term
+
+import "golang.org/x/sys/unix"
+
+func vhangup() error {
+	if _, _, errno := unix.Syscall(unix.SYS_VHANGUP, 0, 0, 0); errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,9 @@
+// +build aix darwin dragonfly freebsd netbsd openbsd solaris zos
+
+package term
+
+import "golang.org/x/sys/unix"
+
+func vhangup() error {
+	return unix.ENOTSUP
+}
@@ -0,0 +1,319 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// EditingMode is the editing mode of the line editor.
+type EditingMode uint8
+
+const (
+	EditingDefault EditingMode = iota // mode set with SetEditingMode
+	EditingEmacs                      // emacs-style editing (initial package mode, see DefaultKeyMap)
+	EditingVi                         // modal vi-style editing
+)
+
+var editingMode = EditingEmacs
+
+// SetEditingMode sets the editing mode for all input functions, which
+// is used if InputOpt.Editing is EditingDefault.
+// Setting EditingDefault resets it to EditingEmacs.
+//
+// In vi mode the input starts in insert mode, where the key map is used
+// as in emacs mode. Esc switches to normal mode (Esc in normal mode cancels
+// the input if enabled with SetCancelOnEsc), in which these commands are
+// available:
+//   h l 0 ^ $ w b   motions (also Left, Right, Home, End etc.)
+//   x X D C S s     delete/change characters or to the end of the input
+//   d c + motion    delete/change (dd and cc: whole input)
+//   i a I A         switch to insert mode
+//   p P             put the last deleted text after/before the cursor
+//   k j             previous/next line in the history
+//   ?               show the key bindings (see EditDumpFunctions)
+// The mode is shown with "(ins) " or "(cmd) " before the prompt
+// (see MsgViInsert and MsgViNormal) and by the shape of the cursor if
+// enabled with SetViCursor.
+func SetEditingMode(mode EditingMode) {
+	if mode == EditingDefault {
+		mode = EditingEmacs
+	}
+	editingMode = mode
+}
+
+// EditingModeFromEnv returns the editing mode the user probably prefers:
+// the value of "set editing-mode" in the inputrc file ($INPUTRC or
+// ~/.inputrc) if there is one, EditingVi if the environment variable
+// VISUAL or EDITOR names a vi-like editor, and EditingEmacs otherwise.
+//   term.SetEditingMode(term.EditingModeFromEnv())
+func EditingModeFromEnv() EditingMode {
+	if name, err := inputrcName(); err == nil {
+		if f, err := os.Open(name); err == nil {
+			defer f.Close()
+			scanner := bufio.NewScanner(f)
+			for scanner.Scan() {
+				fields := strings.Fields(scanner.Text())
+				if len(fields) == 3 && fields[0] == "set" && fields[1] == "editing-mode" {
+					switch fields[2] {
+					case "vi":
+						return EditingVi
+					case "emacs":
+						return EditingEmacs
+					}
+				}
+			}
+		}
+	}
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if fields := strings.Fields(editor); len(fields) > 0 {
+		switch filepath.Base(fields[0]) {
+		case "vi", "vim", "nvim", "nvi", "elvis", "vis", "gvim":
+			return EditingVi
+		}
+	}
+	return EditingEmacs
+}
+
+func (r *reader) vi() bool {
+	mode := r.opt.Editing
+	if mode == EditingDefault {
+		mode = editingMode
+	}
+	return mode == EditingVi
+}
+
+// modeIndicator returns the indicator for the vi mode
+// or "" if vi mode is not used.
+func (r *reader) modeIndicator() string {
+	switch {
+	case !r.vi():
+		return ""
+	case r.viNormal:
+		return msg(MsgViNormal)
+	}
+	return msg(MsgViInsert)
+}
+
+// handleVi processes a key in vi mode. It returns true for handled
+// if the key was processed.
+func (r *reader) handleVi(key []byte) (handled, fin bool, err error) {
+	if key[0] == escape {
+		if len(key) > 1 && (key[1] == '[' || key[1] == 'O') {
+			// escape sequences are processed as usual
+			return false, false, nil
+		}
+		if !r.viNormal {
+			r.setViNormal(true)
+			if len(key) == 1 {
+				return true, false, nil
+			}
+			// Esc followed by a key that was typed quickly
+			key = key[1:]
+		} else if len(key) > 1 {
+			key = key[1:]
+		}
+	}
+	if !r.viNormal {
+		return false, false, nil
+	}
+	ch, _ := utf8.DecodeRune(key)
+	if ch == escape || unicode.IsControl(ch) {
+		return false, false, nil
+	}
+	fin, err = r.viCommand(ch)
+	return true, fin, err
+}
+
+func (r *reader) setViNormal(normal bool) {
+	r.viNormal = normal
+	r.viOp = 0
+	if normal && r.pos > 0 {
+		// like vi the cursor moves back when leaving insert mode
+		r.pos = r.prevPos()
+	}
+	out.WriteString(r.viCursorSeq())
+	r.render()
+}
+
+// viCommand executes a command in normal mode.
+func (r *reader) viCommand(ch rune) (bool, error) {
+	if op := r.viOp; op != 0 {
+		r.viOp = 0
+		from, to, ok := r.viRange(op, ch)
+		if !ok {
+			out.WriteString(bell)
+			return false, nil
+		}
+		r.kill(from, to)
+		if op == 'c' {
+			r.setViNormal(false)
+			return false, nil
+		}
+		r.viClamp()
+		return false, nil
+	}
+	switch ch {
+	case 'h':
+		r.edit(EditBackwardChar)
+	case 'l', ' ':
+		r.moveTo(r.nextPos())
+	case 'w':
+		r.moveTo(r.viWordForward())
+	case 'k', '-':
+		r.historyMove(-1)
+	case 'j', '+':
+		r.historyMove(1)
+	case 'b':
+		r.moveTo(r.viWordBackward())
+	case '0', '^':
+		r.moveTo(0)
+	case '$':
+		r.moveTo(len(r.buf))
+	case 'x':
+		r.kill(r.pos, r.nextPos())
+	case 'X':
+		r.kill(r.prevPos(), r.pos)
+	case 'd', 'c':
+		r.viOp = byte(ch)
+	case 'D':
+		r.kill(r.pos, len(r.buf))
+	case 'C':
+		r.kill(r.pos, len(r.buf))
+		r.setViNormal(false)
+	case 'S':
+		r.kill(0, len(r.buf))
+		r.setViNormal(false)
+	case 's':
+		r.kill(r.pos, r.nextPos())
+		r.setViNormal(false)
+	case 'i':
+		r.setViNormal(false)
+	case 'a':
+		r.moveTo(r.nextPos())
+		r.setViNormal(false)
+	case 'I':
+		r.moveTo(0)
+		r.setViNormal(false)
+	case 'A':
+		r.moveTo(len(r.buf))
+		r.setViNormal(false)
+	case '?':
+		r.showBindings()
+	case 'p', 'P':
+		if len(killBuf) == 0 {
+			break
+		}
+		if ch == 'p' {
+			r.moveTo(r.nextPos())
+		}
+		if fin, err := r.insert(killBuf); fin {
+			return fin, err
+		}
+		r.moveTo(r.prevPos())
+	default:
+		out.WriteString(bell)
+	}
+	r.viClamp()
+	return false, nil
+}
+
+// viRange returns the range for the operator op with the motion ch.
+func (r *reader) viRange(op byte, ch rune) (int, int, bool) {
+	switch ch {
+	case rune(op):
+		return 0, len(r.buf), true
+	case 'w':
+		if op == 'c' {
+			// like vi cw changes to the end of the word
+			return r.pos, r.viWordEnd(), true
+		}
+		return r.pos, r.viWordForward(), true
+	case 'b':
+		return r.viWordBackward(), r.pos, true
+	case '0', '^':
+		return 0, r.pos, true
+	case '$':
+		return r.pos, len(r.buf), true
+	case 'h':
+		return r.prevPos(), r.pos, true
+	case 'l', ' ':
+		return r.pos, r.nextPos(), true
+	}
+	return 0, 0, false
+}
+
+// viClamp keeps the cursor on the last character in normal mode.
+func (r *reader) viClamp() {
+	if r.viNormal && r.viOp == 0 && r.pos > 0 && r.pos == len(r.buf) {
+		r.moveTo(r.prevPos())
+	}
+}
+
+// viClass returns the class of a character for vi's word motions:
+// 0 for white space, 1 for letters, digits and '_', 2 for others.
+func viClass(ch rune) int {
+	switch {
+	case unicode.IsSpace(ch):
+		return 0
+	case ch == '_' || unicode.IsLetter(ch) || unicode.IsDigit(ch):
+		return 1
+	}
+	return 2
+}
+
+// viWordEnd returns the position after the word at the cursor.
+func (r *reader) viWordEnd() int {
+	pos := r.pos
+	if pos == len(r.buf) {
+		return pos
+	}
+	ch, _ := utf8.DecodeRune(r.buf[pos:])
+	class := viClass(ch)
+	for pos < len(r.buf) {
+		ch, n := utf8.DecodeRune(r.buf[pos:])
+		if viClass(ch) != class {
+			break
+		}
+		pos += n
+	}
+	return pos
+}
+
+// viWordForward returns the start of the next word.
+func (r *reader) viWordForward() int {
+	pos := r.viWordEnd()
+	for pos < len(r.buf) {
+		ch, n := utf8.DecodeRune(r.buf[pos:])
+		if viClass(ch) != 0 {
+			break
+		}
+		pos += n
+	}
+	return pos
+}
+
+// viWordBackward returns the start of the current or previous word.
+func (r *reader) viWordBackward() int {
+	pos := r.pos
+	class := 0
+	for pos > 0 {
+		ch, n := utf8.DecodeLastRune(r.buf[:pos])
+		c := viClass(ch)
+		if class == 0 {
+			class = c
+		} else if c != class {
+			break
+		}
+		pos -= n
+	}
+	return pos
+}
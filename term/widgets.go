@@ -0,0 +1,226 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Checkbox shows a checkbox with the label ("[x] label") that is toggled
+// with Space; Enter returns its state. If Esc is typed and SetCancelOnEsc
+// is enabled, ErrCanceled is returned.
+// It panics if stdin and stdout are not connected to a terminal.
+func Checkbox(label string, checked bool) (bool, error) {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	return checkbox(label, checked)
+}
+
+func checkbox(label string, checked bool) (bool, error) {
+	checkIsTerminal()
+	err := runWidget(func() []string {
+		box := ' '
+		if checked {
+			box = 'x'
+		}
+		return []string{fmt.Sprintf("[%c] %s", box, label)}
+	}, func(key string) (bool, error) {
+		if key == " " {
+			checked = !checked
+		}
+		return widgetDone(key)
+	})
+	return checked, err
+}
+
+// RadioGroup shows the options as a radio group, one option per line
+// ("(*) option"). The selection is moved with the Up and Down keys;
+// Enter returns the index of the selected option. If Esc is typed and
+// SetCancelOnEsc is enabled, ErrCanceled is returned.
+// It panics if stdin and stdout are not connected to a terminal or if
+// there are no options.
+func RadioGroup(options []string) (uint, error) {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	return radioGroup(options, 0)
+}
+
+func radioGroup(options []string, selected uint) (uint, error) {
+	checkIsTerminal()
+	if len(options) == 0 {
+		panic("no options")
+	}
+	err := runWidget(func() []string {
+		lines := make([]string, len(options))
+		for i, o := range options {
+			if uint(i) == selected {
+				lines[i] = theme.Accent.Render("(*) " + o)
+			} else {
+				lines[i] = "( ) " + o
+			}
+		}
+		return lines
+	}, func(key string) (bool, error) {
+		switch key {
+		case "\x1b[A", "\x1bOA":
+			if selected > 0 {
+				selected--
+			}
+		case "\x1b[B", "\x1bOB":
+			if selected+1 < uint(len(options)) {
+				selected++
+			}
+		}
+		return widgetDone(key)
+	})
+	return selected, err
+}
+
+// sliderWidth and sliderMinWidth are the max. and min. width of the bar
+// of a slider (the line wraps if the terminal is too narrow).
+const (
+	sliderWidth    = 40
+	sliderMinWidth = 5
+)
+
+// Slider shows a slider with the label for a value in the range [min,max]
+// ("label [=====     ] 50"). The Left and Right keys change the value by
+// step, Home and End set it to min and max; Enter returns it. The initial
+// value is rounded to the nearest step. If Esc is typed and SetCancelOnEsc
+// is enabled, ErrCanceled is returned.
+//   volume, err := term.Slider("Volume", 0, 100, 5, 50)
+// It panics if stdin and stdout are not connected to a terminal or if
+// step <= 0 or max < min.
+func Slider(label string, min, max, step, value float64) (float64, error) {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	checkIsTerminal()
+	if step <= 0 || max < min {
+		panic("invalid range or step")
+	}
+	// the value is min + n*step, so that steps do not accumulate
+	// rounding errors
+	steps := int(math.Floor((max - min) / step))
+	n := int(math.Round((value - min) / step))
+	prec := maxInt(decimals(min), decimals(step))
+	current := func() float64 {
+		v, _ := strconv.ParseFloat(strconv.FormatFloat(min+float64(n)*step, 'f', prec, 64), 64)
+		return math.Min(v, max)
+	}
+	width, _ := getTermSize()
+	barWidth := width - StringWidth(label) - len(strconv.FormatFloat(max, 'f', -1, 64)) - 8
+	barWidth = minInt(maxInt(barWidth, sliderMinWidth), sliderWidth)
+	err := runWidget(func() []string {
+		if n < 0 {
+			n = 0
+		} else if n > steps {
+			n = steps
+		}
+		filled := barWidth
+		if steps > 0 {
+			filled = barWidth * n / steps
+		}
+		bar := theme.Accent.Render(strings.Repeat("=", filled)) + strings.Repeat(" ", maxInt(barWidth-filled, 0))
+		return []string{fmt.Sprintf("%s [%s] %s", label, bar, strconv.FormatFloat(current(), 'f', -1, 64))}
+	}, func(key string) (bool, error) {
+		switch key {
+		case "\x1b[C", "\x1bOC":
+			n++
+		case "\x1b[D", "\x1bOD":
+			n--
+		case "\x1b[H", "\x1bOH", "\x1b[1~", "\x1b[7~":
+			n = 0
+		case "\x1b[F", "\x1bOF", "\x1b[4~", "\x1b[8~":
+			n = steps
+		}
+		return widgetDone(key)
+	})
+	return current(), err
+}
+
+// decimals returns the number of decimal places of f.
+func decimals(f float64) int {
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		return len(s) - i - 1
+	}
+	return 0
+}
+
+// widgetDone returns whether the key ends the input of a component.
+func widgetDone(key string) (bool, error) {
+	switch key {
+	case "\r", "\n":
+		return true, nil
+	case "\x1b":
+		if cancelOnEsc {
+			return true, ErrCanceled
+		}
+	}
+	return false, nil
+}
+
+// runWidget shows the lines of an interactive component and calls handle
+// for each key until it returns true; the lines are shown again after
+// each key.
+func runWidget(lines func() []string, handle func(key string) (bool, error)) error {
+	return runWidgetTick(lines, handle, 0)
+}
+
+// runWidgetTick is like runWidget; if tick > 0, handle is also called
+// with an empty key if no key was typed for the duration tick. Changes
+// after such calls are drawn according to the render profile (see
+// SetRenderProfile).
+func runWidgetTick(lines func() []string, handle func(key string) (bool, error), tick time.Duration) error {
+	restore, err := noEcho(int(inFile.Fd()))
+	if err != nil {
+		return err
+	}
+	defer restore()
+	var keys keyReader
+	defer keys.useTypeahead()()
+	shown := 0
+	done := false
+	var last string
+	var key []byte
+	var drawn time.Time
+	for {
+		ls := lines()
+		s := strings.Join(ls, "\x1b[K\n") + "\x1b[K"
+		final := done || err != nil
+		if !final && s != last && len(key) == 0 && !drawn.IsZero() &&
+			time.Since(drawn) < renderProfile.interval() {
+			// an update without a key, drawn later
+			s = last
+		}
+		writeOut(func() {
+			if s != last {
+				cursorUp(out, shown-1)
+				out.WriteString("\r" + s)
+				drawn = time.Now()
+			}
+			if final {
+				fmt.Fprintln(out)
+			}
+		})
+		if final {
+			return err
+		}
+		if s != last {
+			shown, last = len(ls), s
+		}
+		if tick > 0 {
+			keys.deadline = time.Now().Add(tick)
+		}
+		if key, err = keys.readKey(); err == ErrTimeout && tick > 0 {
+			err = nil
+		} else if err != nil {
+			return err
+		}
+		done, err = handle(string(key))
+	}
+}